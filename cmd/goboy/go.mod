@@ -0,0 +1,13 @@
+module github.com/hayeb/goboy/cmd/goboy
+
+go 1.18
+
+require github.com/hayeb/goboy v0.0.0
+
+require (
+	github.com/alecthomas/participle v0.1.0 // indirect
+	golang.org/x/exp v0.0.0-20190121172915-509febef88a4 // indirect
+)
+
+// The frontend is built from the same checkout as the emulator core
+replace github.com/hayeb/goboy => ../..
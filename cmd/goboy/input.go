@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/banthar/Go-SDL/sdl"
+	"github.com/hayeb/goboy/gameboy"
+)
+
+// A Gameboy button or a frontend action that can be bound to a key or a joystick button
+type action int
+
+const (
+	buttonA action = iota
+	buttonB
+	buttonSelect
+	buttonStart
+	buttonUp
+	buttonDown
+	buttonLeft
+	buttonRight
+	hotkeyFastForward
+	hotkeyPause
+	hotkeySaveState
+	hotkeyLoadState
+	// Selects the save state slot, followed by the actions for slots 1 to 9
+	hotkeySlot0
+)
+
+const stateSlots = 10
+
+var actionNames = map[string]action{
+	"a":           buttonA,
+	"b":           buttonB,
+	"select":      buttonSelect,
+	"start":       buttonStart,
+	"up":          buttonUp,
+	"down":        buttonDown,
+	"left":        buttonLeft,
+	"right":       buttonRight,
+	"fastforward": hotkeyFastForward,
+	"pause":       hotkeyPause,
+	"savestate":   hotkeySaveState,
+	"loadstate":   hotkeyLoadState,
+}
+
+var keyNames = map[string]uint32{
+	"return":      sdl.K_RETURN,
+	"space":       sdl.K_SPACE,
+	"tab":         sdl.K_TAB,
+	"escape":      sdl.K_ESCAPE,
+	"backspace":   sdl.K_BACKSPACE,
+	"up":          sdl.K_UP,
+	"down":        sdl.K_DOWN,
+	"left":        sdl.K_LEFT,
+	"right":       sdl.K_RIGHT,
+	"left shift":  sdl.K_LSHIFT,
+	"right shift": sdl.K_RSHIFT,
+	"left ctrl":   sdl.K_LCTRL,
+	"right ctrl":  sdl.K_RCTRL,
+	"left alt":    sdl.K_LALT,
+	"right alt":   sdl.K_RALT,
+}
+
+func init() {
+	for i := 0; i < stateSlots; i++ {
+		actionNames[fmt.Sprintf("slot%d", i)] = hotkeySlot0 + action(i)
+	}
+	// The SDL key symbols of letters and digits are their ASCII values, the function keys are consecutive
+	for i := 0; i < 26; i++ {
+		keyNames[string(rune('a'+i))] = uint32(sdl.K_a + i)
+	}
+	for i := 0; i < 10; i++ {
+		keyNames[string(rune('0'+i))] = uint32(sdl.K_0 + i)
+	}
+	for i := 0; i < 12; i++ {
+		keyNames[fmt.Sprintf("f%d", i+1)] = uint32(sdl.K_F1 + i)
+	}
+}
+
+/*
+The bindings file is a JSON object that maps action names to key names and joystick button numbers.
+Actions that are left out keep their default binding, for example:
+
+	{"keys": {"start": "s", "fastforward": "left shift"}, "joystick": {"a": 1, "b": 0}}
+*/
+type bindingsFile struct {
+	Keys     map[string]string `json:"keys"`
+	Joystick map[string]uint8  `json:"joystick"`
+}
+
+func defaultBindingsFile() bindingsFile {
+	return bindingsFile{
+		Keys: map[string]string{
+			"a":           "a",
+			"b":           "b",
+			"select":      "space",
+			"start":       "return",
+			"up":          "up",
+			"down":        "down",
+			"left":        "left",
+			"right":       "right",
+			"fastforward": "tab",
+			"pause":       "p",
+			"savestate":   "f5",
+			"loadstate":   "f7",
+			"slot0":       "0",
+			"slot1":       "1",
+			"slot2":       "2",
+			"slot3":       "3",
+			"slot4":       "4",
+			"slot5":       "5",
+			"slot6":       "6",
+			"slot7":       "7",
+			"slot8":       "8",
+			"slot9":       "9",
+		},
+		Joystick: map[string]uint8{
+			"a":      0,
+			"b":      1,
+			"select": 6,
+			"start":  7,
+		},
+	}
+}
+
+type bindings struct {
+	keys    map[uint32]action
+	buttons map[uint8]action
+}
+
+// Loads the bindings from the file on top of the defaults, an empty path only uses the defaults
+func loadBindings(path string) (*bindings, error) {
+	file := defaultBindingsFile()
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		custom := bindingsFile{}
+		if err := json.Unmarshal(data, &custom); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		for name, key := range custom.Keys {
+			file.Keys[name] = key
+		}
+		for name, button := range custom.Joystick {
+			file.Joystick[name] = button
+		}
+	}
+
+	result := &bindings{keys: map[uint32]action{}, buttons: map[uint8]action{}}
+	for name, keyName := range file.Keys {
+		action, ok := actionNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+		key, ok := keyNames[keyName]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q for %s", keyName, name)
+		}
+		if _, bound := result.keys[key]; bound {
+			return nil, fmt.Errorf("key %q is bound to more than one action", keyName)
+		}
+		result.keys[key] = action
+	}
+	for name, button := range file.Joystick {
+		action, ok := actionNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+		if _, bound := result.buttons[button]; bound {
+			return nil, fmt.Errorf("joystick button %d is bound to more than one action", button)
+		}
+		result.buttons[button] = action
+	}
+	return result, nil
+}
+
+// Frontend state that is controlled with the hotkeys
+type hotkeys struct {
+	// Run as fast as possible while the fast-forward key is held
+	fastForward bool
+	paused      bool
+	// Set when the key is pressed, cleared by the main loop
+	saveState bool
+	loadState bool
+	// The save state slot used by the save and load state keys
+	slot int
+}
+
+type controls struct {
+	bindings *bindings
+	input    gameboy.Input
+	hotkeys  hotkeys
+	// Set when the window is closed
+	quit bool
+}
+
+// Joystick axis values within the dead zone are seen as centered
+const joystickDeadZone = 16384
+
+// Handles all pending SDL events
+func (controls *controls) poll() {
+	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+		switch t := event.(type) {
+		case *sdl.KeyboardEvent:
+			if action, ok := controls.bindings.keys[t.Keysym.Sym]; ok {
+				controls.press(action, t.Type == sdl.KEYDOWN)
+			}
+		case *sdl.JoyButtonEvent:
+			if action, ok := controls.bindings.buttons[t.Button]; ok {
+				controls.press(action, t.Type == sdl.JOYBUTTONDOWN)
+			}
+		case *sdl.JoyHatEvent:
+			controls.press(buttonUp, t.Value&sdl.HAT_UP != 0)
+			controls.press(buttonDown, t.Value&sdl.HAT_DOWN != 0)
+			controls.press(buttonLeft, t.Value&sdl.HAT_LEFT != 0)
+			controls.press(buttonRight, t.Value&sdl.HAT_RIGHT != 0)
+		case *sdl.JoyAxisEvent:
+			// The first two axes are the horizontal and vertical axes of the left stick
+			if t.Axis == 0 {
+				controls.press(buttonLeft, t.Value < -joystickDeadZone)
+				controls.press(buttonRight, t.Value > joystickDeadZone)
+			} else if t.Axis == 1 {
+				controls.press(buttonUp, t.Value < -joystickDeadZone)
+				controls.press(buttonDown, t.Value > joystickDeadZone)
+			}
+		case *sdl.QuitEvent:
+			controls.quit = true
+		}
+	}
+}
+
+func (controls *controls) press(action action, pressed bool) {
+	switch action {
+	case buttonA:
+		controls.input.A = pressed
+	case buttonB:
+		controls.input.B = pressed
+	case buttonSelect:
+		controls.input.Select = pressed
+	case buttonStart:
+		controls.input.Start = pressed
+	case buttonUp:
+		controls.input.Up = pressed
+	case buttonDown:
+		controls.input.Down = pressed
+	case buttonLeft:
+		controls.input.Left = pressed
+	case buttonRight:
+		controls.input.Right = pressed
+	case hotkeyFastForward:
+		controls.hotkeys.fastForward = pressed
+	case hotkeyPause:
+		if pressed {
+			controls.hotkeys.paused = !controls.hotkeys.paused
+		}
+	case hotkeySaveState:
+		controls.hotkeys.saveState = controls.hotkeys.saveState || pressed
+	case hotkeyLoadState:
+		controls.hotkeys.loadState = controls.hotkeys.loadState || pressed
+	default:
+		if action >= hotkeySlot0 && pressed {
+			controls.hotkeys.slot = int(action - hotkeySlot0)
+			fmt.Printf("Selected save state slot %d\n", controls.hotkeys.slot)
+		}
+	}
+}
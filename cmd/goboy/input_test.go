@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/banthar/Go-SDL/sdl"
+)
+
+func writeBindings(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "goboy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "bindings.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultBindings(t *testing.T) {
+	bindings, err := loadBindings("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bindings.keys[sdl.K_RETURN] != buttonStart || bindings.keys[sdl.K_TAB] != hotkeyFastForward {
+		t.Errorf("Expected Return to be bound to Start and Tab to fast-forward")
+	}
+	if bindings.buttons[7] != buttonStart {
+		t.Errorf("Expected joystick button 7 to be bound to Start")
+	}
+}
+
+func TestCustomBindings(t *testing.T) {
+	path := writeBindings(t, `{"keys": {"start": "s", "fastforward": "left shift"}, "joystick": {"a": 2}}`)
+	bindings, err := loadBindings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bindings.keys['s'] != buttonStart || bindings.keys[sdl.K_LSHIFT] != hotkeyFastForward {
+		t.Errorf("Expected the bindings from the file to be used")
+	}
+	if _, ok := bindings.keys[sdl.K_RETURN]; ok {
+		t.Errorf("Expected the default Start key to be replaced")
+	}
+	if bindings.keys[sdl.K_a] != buttonA || bindings.buttons[2] != buttonA {
+		t.Errorf("Expected the bindings missing from the file to keep their defaults")
+	}
+}
+
+func TestInvalidBindings(t *testing.T) {
+	for _, content := range []string{
+		`{"keys": {"start": "no such key"}}`,
+		`{"keys": {"jump": "j"}}`,
+		`{"keys": {"start": "a"}}`,
+		`{"joystick": {"b": 0}}`,
+		`not json`,
+	} {
+		if _, err := loadBindings(writeBindings(t, content)); err == nil {
+			t.Errorf("Expected an error for %s", content)
+		}
+	}
+}
+
+func TestControlsPress(t *testing.T) {
+	controls := &controls{}
+
+	controls.press(buttonStart, true)
+	controls.press(hotkeyPause, true)
+	controls.press(hotkeyPause, false)
+	controls.press(hotkeySaveState, true)
+	controls.press(hotkeySaveState, false)
+	controls.press(hotkeySlot0+3, true)
+	controls.press(hotkeySlot0+5, false)
+
+	if !controls.input.Start {
+		t.Errorf("Expected Start to be pressed")
+	}
+	if !controls.hotkeys.paused {
+		t.Errorf("Expected pause to toggle on a key press only")
+	}
+	if !controls.hotkeys.saveState {
+		t.Errorf("Expected the save state request to stay set until it is handled")
+	}
+	if controls.hotkeys.slot != 3 {
+		t.Errorf("Expected slot 3 to be selected, got %d", controls.hotkeys.slot)
+	}
+}
+
+func TestSavePaths(t *testing.T) {
+	if path := statePath("roms/tetris.gb", 2); path != "roms/tetris.ss2" {
+		t.Errorf("Unexpected state path %s", path)
+	}
+	if path := batteryPath("roms/pokemonred.gb"); path != "roms/pokemonred.sav" {
+		t.Errorf("Unexpected battery path %s", path)
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"github.com/hayeb/goboy/gameboy"
+	"github.com/banthar/Go-SDL/sdl"
+	"io/ioutil"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func check(e error) {
+	if e != nil {
+		fmt.Println(e.Error())
+		os.Exit(1)
+	}
+}
+
+func main() {
+
+	rom := flag.String("rom", "", "Rom to be loaded")
+	scale := flag.Int("scale", 4, "Scaling factor to be used. Default is 4, resulting in 4*160 x 4*144 resolution")
+	debug := flag.Bool("debug", false, "Whether to start the debugger")
+	speed := flag.Float64("speed", 1, "Speed factor, should be > 0. Default is 1, 2 runs twice as fast and 0.5 at half speed")
+	bindingsPath := flag.String("bindings", "", "JSON file with key and joystick bindings, the defaults are used for missing bindings")
+	accurate := flag.Bool("accurate", false, "Whether to advance the hardware on every memory access instead of every instruction")
+	record := flag.String("record", "", "File to record the input to from power on, written when the window is closed")
+	play := flag.String("play", "", "Input movie file to play back from power on")
+	hostClock := flag.Bool("hostclock", false, "Whether to run the cartridge clock on the time of the computer, so it keeps going while paused or closed")
+
+	flag.Parse()
+
+	if rom == nil || *rom == "" {
+		fmt.Println("Please specify a rom using -rom")
+		os.Exit(1)
+	}
+
+	if *scale <= 0 {
+		fmt.Println("Invalid scale")
+		os.Exit(1)
+	}
+
+	if *speed <= 0 {
+		fmt.Println("Invalid speed")
+		os.Exit(1)
+	}
+
+	if *debug && (*record != "" || *play != "") {
+		fmt.Println("Movies can not be recorded or played back in the debugger")
+		os.Exit(1)
+	}
+
+	if *hostClock && (*record != "" || *play != "") {
+		fmt.Println("Movies can not be recorded or played back with the cartridge clock on the time of the computer")
+		os.Exit(1)
+	}
+
+	cartridge, error2 := ioutil.ReadFile(*rom)
+	check(error2)
+
+	bindings, err := loadBindings(*bindingsPath)
+	check(err)
+
+	sdl.Init(sdl.INIT_EVERYTHING)
+
+	window := sdl.SetVideoMode(*scale*160, *scale*144, 32, sdl.HWACCEL)
+	defer window.Free()
+
+	sdl.JoystickEventState(sdl.ENABLE)
+	for i := 0; i < sdl.NumJoysticks(); i++ {
+		sdl.JoystickOpen(i)
+	}
+
+	gb, err := gameboy.Initialize(cartridge, &sdlSink{surface: window, scale: *scale}, &gameboy.Options{Debug: *debug, CycleAccurate: *accurate, HostClock: *hostClock})
+	check(err)
+
+	// SDL 1.2 can not drive the rumble of a joystick, so the window title shows the motor instead
+	gb.SetRumble(func(on bool) {
+		if on {
+			sdl.WM_SetCaption("GoBoy (rumble)", "")
+		} else {
+			sdl.WM_SetCaption("GoBoy", "")
+		}
+	})
+
+	var movie *gameboy.Movie
+	if *record != "" {
+		movie = gb.RecordMovie()
+	} else if *play != "" {
+		file, err := os.Open(*play)
+		check(err)
+		movie, err = gameboy.ReadMovie(file)
+		file.Close()
+		check(err)
+		check(gb.PlayMovie(movie))
+	}
+
+	// Movies start with empty cartridge RAM, so they play back the same on every machine
+	battery := ""
+	if gb.HasBattery() && movie == nil {
+		battery = batteryPath(*rom)
+		loadBattery(gb, battery)
+	}
+
+	controls := &controls{bindings: bindings}
+	if *debug {
+		gameboy.RunDebugger(gb, func(input *gameboy.Input) {
+			controls.poll()
+			if controls.quit {
+				saveBattery(gb, battery)
+				os.Exit(0)
+			}
+			*input = controls.input
+		})
+	} else {
+		pacer := newPacer(*speed)
+		for frame := 0; !controls.quit; frame++ {
+			controls.poll()
+			if controls.hotkeys.saveState {
+				controls.hotkeys.saveState = false
+				saveState(gb, statePath(*rom, controls.hotkeys.slot))
+			}
+			if controls.hotkeys.loadState {
+				controls.hotkeys.loadState = false
+				loadState(gb, statePath(*rom, controls.hotkeys.slot))
+			}
+
+			if controls.hotkeys.paused {
+				pacer.wait()
+				continue
+			}
+
+			// The movie sets the buttons during playback
+			if !gb.PlayingMovie() {
+				gb.SetInput(controls.input)
+			}
+			if _, _, err := gb.RunFrame(); err != nil {
+				fmt.Println(err)
+			}
+			// Save the cartridge RAM about once a second, so little is lost when the emulator crashes
+			if frame%60 == 0 {
+				saveBattery(gb, battery)
+			}
+
+			if controls.hotkeys.fastForward {
+				pacer.reset()
+			} else {
+				pacer.wait()
+			}
+		}
+	}
+
+	saveBattery(gb, battery)
+	if *record != "" {
+		gb.StopMovie()
+		file, err := os.Create(*record)
+		check(err)
+		defer file.Close()
+		check(movie.Write(file))
+	}
+}
+
+// Save states are stored next to the ROM, with the slot number in the extension
+func statePath(rom string, slot int) string {
+	return fmt.Sprintf("%s.ss%d", strings.TrimSuffix(rom, filepath.Ext(rom)), slot)
+}
+
+func saveState(gb *gameboy.Gameboy, path string) {
+	file, err := os.Create(path)
+	if err == nil {
+		err = gb.SaveState(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Printf("Saving state to %s failed: %v\n", path, err)
+		return
+	}
+	fmt.Printf("Saved state to %s\n", path)
+}
+
+// Loading a state stops a movie that is recorded or played back
+func loadState(gb *gameboy.Gameboy, path string) {
+	file, err := os.Open(path)
+	if err == nil {
+		err = gb.LoadState(file)
+		file.Close()
+	}
+	if err != nil {
+		fmt.Printf("Loading state from %s failed: %v\n", path, err)
+		return
+	}
+	fmt.Printf("Loaded state from %s\n", path)
+}
+
+// The cartridge RAM is stored next to the ROM as a .sav file, like other emulators do
+func batteryPath(rom string) string {
+	return strings.TrimSuffix(rom, filepath.Ext(rom)) + ".sav"
+}
+
+func loadBattery(gb *gameboy.Gameboy, path string) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err == nil {
+		err = gb.LoadRAM(file)
+		file.Close()
+	}
+	if err != nil {
+		fmt.Printf("Loading cartridge RAM from %s failed: %v\n", path, err)
+	}
+}
+
+// Writes the cartridge RAM when it was changed. It is written to a temporary file first, so a crash
+// while writing does not destroy the old save.
+func saveBattery(gb *gameboy.Gameboy, path string) {
+	if path == "" || !gb.RAMChanged() {
+		return
+	}
+	file, err := os.Create(path + ".tmp")
+	if err == nil {
+		err = gb.SaveRAM(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		fmt.Printf("Saving cartridge RAM to %s failed: %v\n", path, err)
+	}
+}
+
+// Draws the frames to the window, every Gameboy pixel is drawn as a square of scale by scale pixels
+type sdlSink struct {
+	surface *sdl.Surface
+	scale   int
+}
+
+func (sink *sdlSink) ShowFrame(frame *gameboy.Frame) {
+	for j := 0; j < len(frame); j++ {
+		for i := 0; i < len(frame[0]); i++ {
+			rect := sdl.Rect{X: int16(i * sink.scale), Y: int16(j * sink.scale), W: uint16(sink.scale), H: uint16(sink.scale)}
+			sink.surface.FillRect(&rect, sdl.MapRGBA(sink.surface.Format, frame[j][i].R, frame[j][i].G, frame[j][i].B, 0xff))
+		}
+	}
+	sink.surface.Flip()
+}
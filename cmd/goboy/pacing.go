@@ -0,0 +1,41 @@
+package main
+
+import (
+	"time"
+
+	"github.com/hayeb/goboy/gameboy"
+)
+
+// Paces the emulation to the refresh rate of the Gameboy, multiplied by the speed factor
+type pacer struct {
+	frameDuration time.Duration
+	// When the next frame should be started
+	next time.Time
+}
+
+func newPacer(speed float64) *pacer {
+	return &pacer{
+		frameDuration: time.Duration(float64(time.Second) / (gameboy.FrameRate * speed)),
+		next:          time.Now(),
+	}
+}
+
+/*
+Waits until the next frame should be started. When the host could not keep up for more than a frame,
+the pacer starts over from now instead of running the missed frames as fast as possible.
+*/
+func (pacer *pacer) wait() {
+	pacer.next = pacer.next.Add(pacer.frameDuration)
+
+	now := time.Now()
+	if pacer.next.After(now) {
+		time.Sleep(pacer.next.Sub(now))
+	} else if now.Sub(pacer.next) > pacer.frameDuration {
+		pacer.next = now
+	}
+}
+
+// Starts pacing from now, for example after fast-forwarding
+func (pacer *pacer) reset() {
+	pacer.next = time.Now()
+}
@@ -5,7 +5,7 @@ func rLeftCarry(val uint8, c bool) (uint8, bool) {
 }
 
 func rRightCarry(val uint8, c bool) (uint8, bool) {
-	return val>>1 | uint8(btoi(c)<<7), uint8tob(val >> 7)
+	return val>>1 | uint8(btoi(c)<<7), uint8tob(val&0x1)
 }
 
 func rLeft(val uint8) (uint8, bool) {
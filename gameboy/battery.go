@@ -0,0 +1,71 @@
+package gameboy
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// Returns whether the cartridge has a battery that keeps the external RAM and the clock while the power is off
+func (gb *Gameboy) HasBattery() bool {
+	return gb.cartridgeInfo.battery && (gb.cartridgeInfo.ramBytes() > 0 || gb.cartridgeInfo.timer)
+}
+
+/*
+Writes the external RAM in the raw format of .sav files, the RAM banks one after the other followed
+by the real-time clock of MBC3 cartridges. Other emulators use the same format, so saves can be moved
+between them.
+*/
+func (gb *Gameboy) SaveRAM(w io.Writer) error {
+	ram := make([]uint8, gb.cartridgeInfo.ramBytes())
+	for i := range ram {
+		ram[i] = gb.mem.switchableRamBank[i/(8*1024)][i%(8*1024)]
+	}
+	if rtc := gb.mem.cartridgeClock(); rtc != nil {
+		ram = append(ram, rtc.footer()...)
+	}
+	if _, err := w.Write(ram); err != nil {
+		return err
+	}
+	gb.mem.ramChanged = false
+	return nil
+}
+
+/*
+Loads the external RAM written by SaveRAM or another emulator. The clock of MBC3 cartridges is only
+loaded when it is stored after the RAM, other data after the RAM is ignored.
+*/
+func (gb *Gameboy) LoadRAM(r io.Reader) error {
+	ram := make([]uint8, gb.cartridgeInfo.ramBytes())
+	if _, err := io.ReadFull(r, ram); err != nil {
+		return err
+	}
+	if rtc := gb.mem.cartridgeClock(); rtc != nil {
+		footer, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if len(footer) >= rtcFooterSize-4 {
+			rtc.loadFooter(footer)
+		}
+	}
+	for i, val := range ram {
+		if gb.cartridgeInfo.mbc2 {
+			// Only the lower half of the MBC2 RAM exists, other emulators may store anything in the upper half
+			val &= 0x0F
+		}
+		gb.mem.switchableRamBank[i/(8*1024)][i%(8*1024)] = val
+	}
+	gb.mem.ramChanged = false
+	return nil
+}
+
+/*
+Returns whether the external RAM was written or the clock of the cartridge advanced since it was last
+saved or loaded. The clock runs all the time, so a cartridge with a clock has to be saved regularly.
+*/
+func (gb *Gameboy) RAMChanged() bool {
+	if rtc := gb.mem.cartridgeClock(); rtc != nil && rtc.changed {
+		return true
+	}
+	return gb.mem.ramChanged
+}
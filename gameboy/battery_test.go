@@ -0,0 +1,56 @@
+package gameboy
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHasBattery(t *testing.T) {
+	if !bankedCart(0x03, 0x00, 0x03, 2).HasBattery() {
+		t.Errorf("Expected an MBC1+RAM+BATT cartridge to have a battery")
+	}
+	gb, _ := New(headerRom(0x147, 0x02), nil)
+	if gb.HasBattery() {
+		t.Errorf("Expected an MBC1+RAM cartridge not to have a battery")
+	}
+}
+
+func TestSaveRAMRoundTrip(t *testing.T) {
+	gb := bankedCart(0x03, 0x00, 0x03, 2)
+	gb.Write(0x0000, 0x0A)
+	gb.Write(0x6000, 0x01)
+	gb.Write(0xA000, 0x11)
+	gb.Write(0x4000, 0x03)
+	gb.Write(0xBFFF, 0x22)
+	if !gb.RAMChanged() {
+		t.Errorf("Expected a write to the external RAM to be noticed")
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := gb.SaveRAM(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if gb.RAMChanged() {
+		t.Errorf("Expected the RAM to be unchanged after saving")
+	}
+	saved := buffer.Bytes()
+	if len(saved) != 32*1024 || saved[0] != 0x11 || saved[4*8*1024-1] != 0x22 {
+		t.Fatalf("Unexpected save of %d bytes", len(saved))
+	}
+
+	loaded := bankedCart(0x03, 0x00, 0x03, 2)
+	loaded.Write(0x0000, 0x0A)
+	loaded.Write(0x6000, 0x01)
+	// Trailing data like a clock is ignored
+	if err := loaded.LoadRAM(bytes.NewReader(append(saved, make([]uint8, 48)...))); err != nil {
+		t.Fatal(err)
+	}
+	loaded.Write(0x4000, 0x03)
+	if loaded.Read(0xBFFF) != 0x22 {
+		t.Errorf("Expected 0x22 in the last RAM bank, got %#02x", loaded.Read(0xBFFF))
+	}
+
+	if err := loaded.LoadRAM(bytes.NewReader(saved[:100])); err == nil {
+		t.Errorf("Expected an error loading a short save")
+	}
+}
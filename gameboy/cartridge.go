@@ -15,6 +15,12 @@ type cartridgeInfo struct {
 	mbc1         bool
 	mbc2         bool
 	mbc3         bool
+	mbc5         bool
+	battery      bool
+	// The cartridge has a real-time clock
+	timer bool
+	// The cartridge has a rumble motor
+	rumble bool
 }
 
 type cartridgeTypeCode int
@@ -31,6 +37,9 @@ const (
 	rom_mmm01
 	rom_mmm01_sram
 	rom_mmm01_sram_batt
+	rom_mbc3_timer_batt
+	rom_mbc3_timer_ram_batt
+	rom_mbc3
 	rom_mbc3_ram
 	rom_mbc3_ram_batt
 	rom_mbc5
@@ -52,6 +61,7 @@ const (
 	ram_kbit_64
 	ram_kbit_256
 	ram_mbit_1
+	ram_kbit_512
 )
 
 type romSizeCode int
@@ -64,6 +74,11 @@ const (
 	rom_mbit_4
 	rom_mbit_8
 	rom_mbit_16
+	rom_mbit_32
+	rom_mbit_64
+	rom_mbit_9
+	rom_mbit_10
+	rom_mbit_12
 )
 
 type gameBoyType int
@@ -108,12 +123,18 @@ func (cartInfo *cartridgeInfo) cartridgeTypeCodeString() string {
 		return "ROM+MMM01+SRAM"
 	case rom_mmm01_sram_batt:
 		return "ROM+MMMM01+SRAM+BATT"
+	case rom_mbc3_timer_batt:
+		return "ROM+MBC3+TIMER+BATT"
+	case rom_mbc3_timer_ram_batt:
+		return "ROM+MBC3+TIMER+RAM+BATT"
+	case rom_mbc3:
+		return "ROM+MBC3"
 	case rom_mbc3_ram:
 		return "ROM+MBC3+RAM"
 	case rom_mbc3_ram_batt:
 		return "ROM+MBC3+RAM+BATT"
 	case rom_mbc5:
-		return "ROM~MBC5"
+		return "ROM+MBC5"
 	case rom_mbc5_ram:
 		return "ROM+MBC5+RAM"
 	case rom_mbc5_ram_batt:
@@ -146,11 +167,21 @@ func (cartInfo *cartridgeInfo) romSizeCodeString() string {
 	case rom_mbit_2:
 		return "2 Mbit"
 	case rom_mbit_4:
-		return "4 Mit"
+		return "4 Mbit"
 	case rom_mbit_8:
 		return "8 Mbit"
 	case rom_mbit_16:
 		return "16 Mbit"
+	case rom_mbit_32:
+		return "32 Mbit"
+	case rom_mbit_64:
+		return "64 Mbit"
+	case rom_mbit_9:
+		return "9 Mbit"
+	case rom_mbit_10:
+		return "10 Mbit"
+	case rom_mbit_12:
+		return "12 Mbit"
 	default:
 		return ""
 	}
@@ -168,108 +199,125 @@ func (cartInfo *cartridgeInfo) ramSizeCodeString() string {
 		return "256 Kbit"
 	case ram_mbit_1:
 		return "1 Mbit"
+	case ram_kbit_512:
+		return "512 Kbit"
 	default:
 		return ""
 	}
 }
 
+// Only 0x03 enables the Super Gameboy functions, other values are ignored by the hardware
 func gameboyType(typeCode uint8) gameBoyType {
-	switch typeCode {
-	case 0x00:
-		return type_gameboy
-	case 0x03:
+	if typeCode == 0x03 {
 		return type_super_gameboy
-	default:
-		panic(fmt.Sprintf("Unknown system typecode %d", typeCode))
 	}
+	return type_gameboy
 }
 
-func typeCode(typeCode uint8) cartridgeTypeCode {
+func typeCode(typeCode uint8) (cartridgeTypeCode, error) {
 	switch typeCode {
 	case 0x0:
-		return rom_only
+		return rom_only, nil
 	case 0x1:
-		return rom_mbc1
+		return rom_mbc1, nil
 	case 0x2:
-		return rom_mbc1_ram
+		return rom_mbc1_ram, nil
 	case 0x3:
-		return rom_mbc1_ram_bat
+		return rom_mbc1_ram_bat, nil
 	case 0x5:
-		return rom_mbc2
+		return rom_mbc2, nil
 	case 0x6:
-		return rom_mbc2_batt
+		return rom_mbc2_batt, nil
 	case 0x8:
-		return rom_ram
+		return rom_ram, nil
 	case 0x9:
-		return rom_ram_battery
+		return rom_ram_battery, nil
 	case 0xB:
-		return rom_mmm01
+		return rom_mmm01, nil
 	case 0xC:
-		return rom_mmm01_sram
+		return rom_mmm01_sram, nil
 	case 0xD:
-		return rom_mmm01_sram_batt
+		return rom_mmm01_sram_batt, nil
+	case 0xF:
+		return rom_mbc3_timer_batt, nil
+	case 0x10:
+		return rom_mbc3_timer_ram_batt, nil
+	case 0x11:
+		return rom_mbc3, nil
 	case 0x12:
-		return rom_mbc3_ram
+		return rom_mbc3_ram, nil
 	case 0x13:
-		return rom_mbc3_ram_batt
+		return rom_mbc3_ram_batt, nil
 	case 0x19:
-		return rom_mbc5
+		return rom_mbc5, nil
 	case 0x1A:
-		return rom_mbc5_ram
+		return rom_mbc5_ram, nil
 	case 0x1B:
-		return rom_mbc5_ram_batt
+		return rom_mbc5_ram_batt, nil
 	case 0x1C:
-		return rom_mbc5_rumble
+		return rom_mbc5_rumble, nil
 	case 0x1D:
-		return rom_mbc5_rumble_sram
+		return rom_mbc5_rumble_sram, nil
 	case 0x1E:
-		return rom_mbc5_rumble_sram_batt
+		return rom_mbc5_rumble_sram_batt, nil
 	case 0x1F:
-		return pocket_camera
+		return pocket_camera, nil
 	case 0xFD:
-		return bandai_tama5
+		return bandai_tama5, nil
 	case 0xFE:
-		return hudson_huc3
+		return hudson_huc3, nil
 	default:
-		panic(fmt.Sprintf("Unknown type code %d", typeCode))
+		return 0, &HeaderError{Field: "cartridge type", Address: 0x147, Value: typeCode}
 	}
 }
 
-func uint8ToromSizeCode(romcode uint8) romSizeCode {
+func uint8ToromSizeCode(romcode uint8) (romSizeCode, error) {
 	switch romcode {
 	case 0:
-		return rom_kbit_256
+		return rom_kbit_256, nil
 	case 1:
-		return rom_kbit_512
+		return rom_kbit_512, nil
 	case 2:
-		return rom_mbit_1
+		return rom_mbit_1, nil
 	case 3:
-		return rom_mbit_2
+		return rom_mbit_2, nil
 	case 4:
-		return rom_mbit_4
+		return rom_mbit_4, nil
 	case 5:
-		return rom_mbit_8
+		return rom_mbit_8, nil
 	case 6:
-		return rom_mbit_16
+		return rom_mbit_16, nil
+	case 7:
+		return rom_mbit_32, nil
+	case 8:
+		return rom_mbit_64, nil
+	case 0x52:
+		return rom_mbit_9, nil
+	case 0x53:
+		return rom_mbit_10, nil
+	case 0x54:
+		return rom_mbit_12, nil
 	default:
-		panic(fmt.Sprintf("Unknown ROM size code %d", romcode))
+		return 0, &HeaderError{Field: "ROM size", Address: 0x148, Value: romcode}
 	}
 }
 
-func uint8ToramSizeCode(ramSizeCode uint8) ramSizeCode {
+func uint8ToramSizeCode(ramSizeCode uint8) (ramSizeCode, error) {
 	switch ramSizeCode {
 	case 0:
-		return ram_none
+		return ram_none, nil
 	case 1:
-		return ram_kbit_16
+		return ram_kbit_16, nil
 	case 2:
-		return ram_kbit_64
+		return ram_kbit_64, nil
 	case 3:
-		return ram_kbit_256
+		return ram_kbit_256, nil
 	case 4:
-		return ram_mbit_1
+		return ram_mbit_1, nil
+	case 5:
+		return ram_kbit_512, nil
 	default:
-		panic(fmt.Sprintf("Unknown RAM size code %d", ramSizeCode))
+		return 0, &HeaderError{Field: "RAM size", Address: 0x149, Value: ramSizeCode}
 	}
 }
 
@@ -281,29 +329,45 @@ func cartridgeTitle(cartridge []byte) string {
 	return title
 }
 
+// Only 0x00 marks a Japanese cartridge, the destination code is not checked by the hardware
 func localization(code uint8) string {
-	if code == 0x1 {
-		return "Non-Japanese"
-	} else if code == 0x0 {
+	if code == 0x0 {
 		return "Japanese"
-	} else {
-		panic(fmt.Sprintf("No localization for code %d", code))
 	}
+	return "Non-Japanese"
 }
 
-func createCartridgeInfo(cartridge []byte) *cartridgeInfo {
-	typeCode := typeCode(cartridge[0x147])
+func createCartridgeInfo(cartridge []byte) (*cartridgeInfo, error) {
+	if len(cartridge) < 0x150 {
+		return nil, &RomSizeError{Size: len(cartridge)}
+	}
+	typeCode, err := typeCode(cartridge[0x147])
+	if err != nil {
+		return nil, err
+	}
+	romSize, err := uint8ToromSizeCode(cartridge[0x148])
+	if err != nil {
+		return nil, err
+	}
+	ramSize, err := uint8ToramSizeCode(cartridge[0x149])
+	if err != nil {
+		return nil, err
+	}
 	return &cartridgeInfo{
 		Name:         cartridgeTitle(cartridge),
 		CartType:     typeCode,
 		System:       gameboyType(cartridge[0x146]),
-		romSize:      uint8ToromSizeCode(cartridge[0x148]),
-		ramSize:      uint8ToramSizeCode(cartridge[0x149]),
+		romSize:      romSize,
+		ramSize:      ramSize,
 		Localization: localization(cartridge[0x14A]),
 		mbc1:         isMBC1(typeCode),
 		mbc2:         isMBC2(typeCode),
 		mbc3:         isMBC3(typeCode),
-	}
+		mbc5:         isMBC5(typeCode),
+		battery:      hasBattery(typeCode),
+		timer:        typeCode == rom_mbc3_timer_batt || typeCode == rom_mbc3_timer_ram_batt,
+		rumble:       typeCode == rom_mbc5_rumble || typeCode == rom_mbc5_rumble_sram || typeCode == rom_mbc5_rumble_sram_batt,
+	}, nil
 }
 
 func cartridgeInfoString(cartridgeInfo cartridgeInfo) string {
@@ -327,5 +391,60 @@ func isMBC2(code cartridgeTypeCode) bool {
 }
 
 func isMBC3(code cartridgeTypeCode) bool {
-	return code == rom_mbc3_ram || code == rom_mbc3_ram_batt
+	switch code {
+	case rom_mbc3_timer_batt, rom_mbc3_timer_ram_batt, rom_mbc3, rom_mbc3_ram, rom_mbc3_ram_batt:
+		return true
+	}
+	return false
+}
+
+func isMBC5(code cartridgeTypeCode) bool {
+	switch code {
+	case rom_mbc5, rom_mbc5_ram, rom_mbc5_ram_batt, rom_mbc5_rumble, rom_mbc5_rumble_sram, rom_mbc5_rumble_sram_batt:
+		return true
+	}
+	return false
+}
+
+func hasBattery(code cartridgeTypeCode) bool {
+	switch code {
+	case rom_mbc1_ram_bat, rom_mbc2_batt, rom_ram_battery, rom_mmm01_sram_batt, rom_mbc3_timer_batt,
+		rom_mbc3_timer_ram_batt, rom_mbc3_ram_batt, rom_mbc5_ram_batt, rom_mbc5_rumble_sram_batt:
+		return true
+	}
+	return false
+}
+
+// The size of the external RAM in bytes. MBC2 has 512 half-bytes of RAM built in, stored as bytes.
+func (cartInfo *cartridgeInfo) ramBytes() int {
+	if cartInfo.mbc2 {
+		return 512
+	}
+	switch cartInfo.ramSize {
+	case ram_kbit_16:
+		return 2 * 1024
+	case ram_kbit_64:
+		return 8 * 1024
+	case ram_kbit_256:
+		return 32 * 1024
+	case ram_mbit_1:
+		return 128 * 1024
+	case ram_kbit_512:
+		return 64 * 1024
+	}
+	return 0
+}
+
+// The number of 16 kB ROM banks
+func (cartInfo *cartridgeInfo) romBanks() int {
+	switch cartInfo.romSize {
+	case rom_mbit_9:
+		return 72
+	case rom_mbit_10:
+		return 80
+	case rom_mbit_12:
+		return 96
+	}
+	// The other sizes double from 2 banks for rom_kbit_256 up to 512 banks for rom_mbit_64
+	return 2 << uint(cartInfo.romSize)
 }
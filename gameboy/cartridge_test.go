@@ -0,0 +1,84 @@
+package gameboy
+
+import "testing"
+
+func headerRom(address uint16, value uint8) []uint8 {
+	rom := make([]uint8, 32*1024)
+	rom[address] = value
+	return rom
+}
+
+func TestUnknownHeaderValues(t *testing.T) {
+	for _, address := range []uint16{0x147, 0x148, 0x149} {
+		_, err := Initialize(headerRom(address, 0xEE), nil, &Options{})
+
+		headerErr, ok := err.(*HeaderError)
+		if !ok {
+			t.Errorf("Expected a *HeaderError for %#04x, got %v", address, err)
+			continue
+		}
+		if headerErr.Address != address || headerErr.Value != 0xEE {
+			t.Errorf("Expected the error to point at %#04x, got %+v", address, headerErr)
+		}
+	}
+}
+
+func TestIgnoredHeaderValues(t *testing.T) {
+	// A CGB flag in the SGB byte and an unknown destination code do not matter to the emulation
+	for _, address := range []uint16{0x146, 0x14A} {
+		if _, err := Initialize(headerRom(address, 0x80), nil, &Options{}); err != nil {
+			t.Errorf("Expected %#04x to be ignored, got %v", address, err)
+		}
+	}
+}
+
+func TestRomSize(t *testing.T) {
+	for _, size := range []int{0, 0x100, 0x7FFF} {
+		_, err := Initialize(make([]uint8, size), nil, &Options{})
+		if _, ok := err.(*RomSizeError); !ok {
+			t.Errorf("Expected a *RomSizeError for %d bytes, got %v", size, err)
+		}
+	}
+}
+
+func TestRomBanks(t *testing.T) {
+	banks := map[uint8]int{0x00: 2, 0x05: 64, 0x08: 512, 0x52: 72, 0x53: 80, 0x54: 96}
+	for code, expected := range banks {
+		rom := headerRom(0x148, code)
+		rom = append(rom, make([]uint8, expected*16*1024-len(rom))...)
+		gb, err := Initialize(rom, nil, &Options{})
+		if err != nil {
+			t.Errorf("Expected ROM size code %#02x to be supported, got %v", code, err)
+			continue
+		}
+		if len(gb.mem.switchableRomBank) != expected {
+			t.Errorf("Expected %d banks for ROM size code %#02x, got %d", expected, code, len(gb.mem.switchableRomBank))
+		}
+	}
+}
+
+func TestRamBanks(t *testing.T) {
+	banks := map[uint8]int{0x00: 0, 0x02: 1, 0x03: 4, 0x04: 16, 0x05: 8}
+	for code, expected := range banks {
+		gb, err := Initialize(headerRom(0x149, code), nil, &Options{})
+		if err != nil {
+			t.Errorf("Expected RAM size code %#02x to be supported, got %v", code, err)
+			continue
+		}
+		if len(gb.mem.switchableRamBank) != expected {
+			t.Errorf("Expected %d banks for RAM size code %#02x, got %d", expected, code, len(gb.mem.switchableRamBank))
+		}
+	}
+}
+
+// A ROM file that is smaller than its header says is mirrored across the banks of the header
+func TestTruncatedRom(t *testing.T) {
+	gb := bankedCart(0x19, 0x04, 0x00, 4)
+	if len(gb.mem.switchableRomBank) != 4 {
+		t.Errorf("Expected only the 4 banks in the file, got %d", len(gb.mem.switchableRomBank))
+	}
+	gb.Write(0x2000, 0x06)
+	if bank := gb.Read(0x4200); bank != 2 {
+		t.Errorf("Expected bank 6 to mirror bank 2, got %d", bank)
+	}
+}
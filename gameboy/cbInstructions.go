@@ -10,49 +10,149 @@ type cbInstruction struct {
 	executor cbInstructionExecutor
 }
 
-type cbInstructionExecutor func(mem *memory, reg *register, cbInstr *cbInstruction) int
+// Executes the CB instruction and returns whether its action was taken, like instructionExecutor
+type cbInstructionExecutor func(mem *memory, reg *register, cbInstr *cbInstruction) bool
 
-func createCBInstructionMap() *map[uint8]*cbInstruction {
-	return &map[uint8]*cbInstruction{
-		0x11: newCBInstruction("RL C", 2, 8, rl_c),
+func (cbInstr *cbInstruction) duration(taken bool) int {
+	if taken || cbInstr.noopDuration == 0 {
+		return cbInstr.actionDuration
+	}
+	return cbInstr.noopDuration
+}
+
+/*
+Creates the decode table of CB-prefixed instructions, indexed by the byte following the prefix.
+The opcode encodes the operation in the upper bits and the operand in the lowest three bits
+(B, C, D, E, H, L, (HL), A). The durations include the fetch of the CB prefix itself.
+*/
+func createCBInstructionTable() [256]*cbInstruction {
+	return [256]*cbInstruction{
+		0x00: newCBInstruction("RLC B", 2, 8, rlcB),
+		0x01: newCBInstruction("RLC C", 2, 8, rlcC),
+		0x02: newCBInstruction("RLC D", 2, 8, rlcD),
+		0x03: newCBInstruction("RLC E", 2, 8, rlcE),
+		0x04: newCBInstruction("RLC H", 2, 8, rlcH),
+		0x05: newCBInstruction("RLC L", 2, 8, rlcL),
+		0x06: newCBInstruction("RLC (HL)", 2, 16, rlcHL),
+		0x07: newCBInstruction("RLC A", 2, 8, rlcA),
+		0x08: newCBInstruction("RRC B", 2, 8, rrcB),
+		0x09: newCBInstruction("RRC C", 2, 8, rrcC),
+		0x0a: newCBInstruction("RRC D", 2, 8, rrcD),
+		0x0b: newCBInstruction("RRC E", 2, 8, rrcE),
+		0x0c: newCBInstruction("RRC H", 2, 8, rrcH),
+		0x0d: newCBInstruction("RRC L", 2, 8, rrcL),
+		0x0e: newCBInstruction("RRC (HL)", 2, 16, rrcHL),
+		0x0f: newCBInstruction("RRC A", 2, 8, rrcA),
+
+		0x10: newCBInstruction("RL B", 2, 8, rlB),
+		0x11: newCBInstruction("RL C", 2, 8, rlC),
+		0x12: newCBInstruction("RL D", 2, 8, rlD),
+		0x13: newCBInstruction("RL E", 2, 8, rlE),
+		0x14: newCBInstruction("RL H", 2, 8, rlH),
+		0x15: newCBInstruction("RL L", 2, 8, rlL),
+		0x16: newCBInstruction("RL (HL)", 2, 16, rlHL),
+		0x17: newCBInstruction("RL A", 2, 8, rlA),
+		0x18: newCBInstruction("RR B", 2, 8, rrB),
+		0x19: newCBInstruction("RR C", 2, 8, rrC),
 		0x1a: newCBInstruction("RR D", 2, 8, rrD),
 		0x1b: newCBInstruction("RR E", 2, 8, rrE),
-		0x19: newCBInstruction("RR C", 2, 8, rrC),
-
+		0x1c: newCBInstruction("RR H", 2, 8, rrH),
+		0x1d: newCBInstruction("RR L", 2, 8, rrL),
+		0x1e: newCBInstruction("RR (HL)", 2, 16, rrHL),
+		0x1f: newCBInstruction("RR A", 2, 8, rrA),
+
+		0x20: newCBInstruction("SLA B", 2, 8, slaB),
+		0x21: newCBInstruction("SLA C", 2, 8, slaC),
+		0x22: newCBInstruction("SLA D", 2, 8, slaD),
+		0x23: newCBInstruction("SLA E", 2, 8, slaE),
+		0x24: newCBInstruction("SLA H", 2, 8, slaH),
+		0x25: newCBInstruction("SLA L", 2, 8, slaL),
 		0x26: newCBInstruction("SLA (HL)", 2, 16, slaHL),
 		0x27: newCBInstruction("SLA A", 2, 8, slaA),
+		0x28: newCBInstruction("SRA B", 2, 8, sraB),
+		0x29: newCBInstruction("SRA C", 2, 8, sraC),
+		0x2a: newCBInstruction("SRA D", 2, 8, sraD),
+		0x2b: newCBInstruction("SRA E", 2, 8, sraE),
+		0x2c: newCBInstruction("SRA H", 2, 8, sraH),
+		0x2d: newCBInstruction("SRA L", 2, 8, sraL),
+		0x2e: newCBInstruction("SRA (HL)", 2, 16, sraHL),
+		0x2f: newCBInstruction("SRA A", 2, 8, sraA),
 
 		0x30: newCBInstruction("SWAP B", 2, 8, swapB),
+		0x31: newCBInstruction("SWAP C", 2, 8, swapC),
+		0x32: newCBInstruction("SWAP D", 2, 8, swapD),
 		0x33: newCBInstruction("SWAP E", 2, 8, swapE),
+		0x34: newCBInstruction("SWAP H", 2, 8, swapH),
+		0x35: newCBInstruction("SWAP L", 2, 8, swapL),
+		0x36: newCBInstruction("SWAP (HL)", 2, 16, swapHL),
 		0x37: newCBInstruction("SWAP A", 2, 8, swapA),
 		0x38: newCBInstruction("SRL B", 2, 8, srlB),
+		0x39: newCBInstruction("SRL C", 2, 8, srlC),
 		0x3a: newCBInstruction("SRL D", 2, 8, srlD),
 		0x3b: newCBInstruction("SRL E", 2, 8, srlE),
+		0x3c: newCBInstruction("SRL H", 2, 8, srlH),
+		0x3d: newCBInstruction("SRL L", 2, 8, srlL),
+		0x3e: newCBInstruction("SRL (HL)", 2, 16, srlHL),
 		0x3f: newCBInstruction("SRL A", 2, 8, srlA),
 
 		0x40: newCBInstruction("BIT 0,B", 2, 8, bit_0_b),
 		0x41: newCBInstruction("BIT 0,C", 2, 8, bit_0_c),
+		0x42: newCBInstruction("BIT 0,D", 2, 8, bit_0_d),
+		0x43: newCBInstruction("BIT 0,E", 2, 8, bit_0_e),
+		0x44: newCBInstruction("BIT 0,H", 2, 8, bit_0_h),
+		0x45: newCBInstruction("BIT 0,L", 2, 8, bit_0_l),
+		0x46: newCBInstruction("BIT 0,(HL)", 2, 12, bit_0_hl),
 		0x47: newCBInstruction("BIT 0,A", 2, 8, bit_0_a),
 		0x48: newCBInstruction("BIT 1,B", 2, 8, bit_1_b),
-		0x4e: newCBInstruction("BIT 1,(HL)", 2, 8, bit_1_hl),
+		0x49: newCBInstruction("BIT 1,C", 2, 8, bit_1_c),
+		0x4a: newCBInstruction("BIT 1,D", 2, 8, bit_1_d),
+		0x4b: newCBInstruction("BIT 1,E", 2, 8, bit_1_e),
+		0x4c: newCBInstruction("BIT 1,H", 2, 8, bit_1_h),
+		0x4d: newCBInstruction("BIT 1,L", 2, 8, bit_1_l),
+		0x4e: newCBInstruction("BIT 1,(HL)", 2, 12, bit_1_hl),
 		0x4f: newCBInstruction("BIT 1,A", 2, 8, bit_1_a),
 
 		0x50: newCBInstruction("BIT 2,B", 2, 8, bit_2_b),
+		0x51: newCBInstruction("BIT 2,C", 2, 8, bit_2_c),
+		0x52: newCBInstruction("BIT 2,D", 2, 8, bit_2_d),
+		0x53: newCBInstruction("BIT 2,E", 2, 8, bit_2_e),
+		0x54: newCBInstruction("BIT 2,H", 2, 8, bit_2_h),
+		0x55: newCBInstruction("BIT 2,L", 2, 8, bit_2_l),
+		0x56: newCBInstruction("BIT 2,(HL)", 2, 12, bit_2_hl),
 		0x57: newCBInstruction("BIT 2,A", 2, 8, bit_2_a),
 		0x58: newCBInstruction("BIT 3,B", 2, 8, bit_3_b),
+		0x59: newCBInstruction("BIT 3,C", 2, 8, bit_3_c),
+		0x5a: newCBInstruction("BIT 3,D", 2, 8, bit_3_d),
+		0x5b: newCBInstruction("BIT 3,E", 2, 8, bit_3_e),
+		0x5c: newCBInstruction("BIT 3,H", 2, 8, bit_3_h),
+		0x5d: newCBInstruction("BIT 3,L", 2, 8, bit_3_l),
+		0x5e: newCBInstruction("BIT 3,(HL)", 2, 12, bit_3_hl),
 		0x5f: newCBInstruction("BIT 3,A", 2, 8, bit_3_a),
 
 		0x60: newCBInstruction("BIT 4,B", 2, 8, bit_4_b),
 		0x61: newCBInstruction("BIT 4,C", 2, 8, bit_4_c),
+		0x62: newCBInstruction("BIT 4,D", 2, 8, bit_4_d),
+		0x63: newCBInstruction("BIT 4,E", 2, 8, bit_4_e),
+		0x64: newCBInstruction("BIT 4,H", 2, 8, bit_4_h),
+		0x65: newCBInstruction("BIT 4,L", 2, 8, bit_4_l),
+		0x66: newCBInstruction("BIT 4,(HL)", 2, 12, bit_4_hl),
+		0x67: newCBInstruction("BIT 4,A", 2, 8, bit_4_a),
 		0x68: newCBInstruction("BIT 5,B", 2, 8, bit_5_b),
 		0x69: newCBInstruction("BIT 5,C", 2, 8, bit_5_c),
-		0x6e: newCBInstruction("BIt 5,D", 2, 8, bit_5_d),
+		0x6a: newCBInstruction("BIT 5,D", 2, 8, bit_5_d),
+		0x6b: newCBInstruction("BIT 5,E", 2, 8, bit_5_e),
+		0x6c: newCBInstruction("BIT 5,H", 2, 8, bit_5_h),
+		0x6d: newCBInstruction("BIT 5,L", 2, 8, bit_5_l),
+		0x6e: newCBInstruction("BIT 5,(HL)", 2, 12, bit_5_hl),
 		0x6f: newCBInstruction("BIT 5,A", 2, 8, bit_5_a),
 
 		0x70: newCBInstruction("BIT 6,B", 2, 8, bit_6_b),
 		0x71: newCBInstruction("BIT 6,C", 2, 8, bit_6_c),
+		0x72: newCBInstruction("BIT 6,D", 2, 8, bit_6_d),
+		0x73: newCBInstruction("BIT 6,E", 2, 8, bit_6_e),
 		0x74: newCBInstruction("BIT 6,H", 2, 8, bit_6_h),
-		0x76: newCBInstruction("BIT 6,(HL)", 2, 8, bit_6_hl),
+		0x75: newCBInstruction("BIT 6,L", 2, 8, bit_6_l),
+		0x76: newCBInstruction("BIT 6,(HL)", 2, 12, bit_6_hl),
 		0x77: newCBInstruction("BIT 6,A", 2, 8, bit_6_a),
 		0x78: newCBInstruction("BIT 7,B", 2, 8, bit_7_b),
 		0x79: newCBInstruction("BIT 7,C", 2, 8, bit_7_c),
@@ -60,43 +160,152 @@ func createCBInstructionMap() *map[uint8]*cbInstruction {
 		0x7b: newCBInstruction("BIT 7,E", 2, 8, bit_7_e),
 		0x7c: newCBInstruction("BIT 7,H", 2, 8, bit_7_h),
 		0x7d: newCBInstruction("BIT 7,L", 2, 8, bit_7_l),
-		0x7e: newCBInstruction("BIT 7,(HL)", 2, 16, bit_7_hl),
+		0x7e: newCBInstruction("BIT 7,(HL)", 2, 12, bit_7_hl),
 		0x7f: newCBInstruction("BIT 7,A", 2, 8, bit_7_a),
 
+		0x80: newCBInstruction("RES 0,B", 2, 8, res0B),
+		0x81: newCBInstruction("RES 0,C", 2, 8, res0C),
+		0x82: newCBInstruction("RES 0,D", 2, 8, res0D),
+		0x83: newCBInstruction("RES 0,E", 2, 8, res0E),
+		0x84: newCBInstruction("RES 0,H", 2, 8, res0H),
+		0x85: newCBInstruction("RES 0,L", 2, 8, res0L),
 		0x86: newCBInstruction("RES 0,(HL)", 2, 16, res0hl),
 		0x87: newCBInstruction("RES 0,A", 2, 8, res0A),
+		0x88: newCBInstruction("RES 1,B", 2, 8, res1B),
+		0x89: newCBInstruction("RES 1,C", 2, 8, res1C),
+		0x8a: newCBInstruction("RES 1,D", 2, 8, res1D),
+		0x8b: newCBInstruction("RES 1,E", 2, 8, res1E),
+		0x8c: newCBInstruction("RES 1,H", 2, 8, res1H),
+		0x8d: newCBInstruction("RES 1,L", 2, 8, res1L),
 		0x8e: newCBInstruction("RES 1,(HL)", 2, 16, res1hl),
-
+		0x8f: newCBInstruction("RES 1,A", 2, 8, res1A),
+
+		0x90: newCBInstruction("RES 2,B", 2, 8, res2B),
+		0x91: newCBInstruction("RES 2,C", 2, 8, res2C),
+		0x92: newCBInstruction("RES 2,D", 2, 8, res2D),
+		0x93: newCBInstruction("RES 2,E", 2, 8, res2E),
+		0x94: newCBInstruction("RES 2,H", 2, 8, res2H),
+		0x95: newCBInstruction("RES 2,L", 2, 8, res2L),
 		0x96: newCBInstruction("RES 2,(HL)", 2, 16, res2hl),
-		0x9e: newCBInstruction("RES 3,(HL)", 2, 8, res3hl),
-
+		0x97: newCBInstruction("RES 2,A", 2, 8, res2A),
+		0x98: newCBInstruction("RES 3,B", 2, 8, res3B),
+		0x99: newCBInstruction("RES 3,C", 2, 8, res3C),
+		0x9a: newCBInstruction("RES 3,D", 2, 8, res3D),
+		0x9b: newCBInstruction("RES 3,E", 2, 8, res3E),
+		0x9c: newCBInstruction("RES 3,H", 2, 8, res3H),
+		0x9d: newCBInstruction("RES 3,L", 2, 8, res3L),
+		0x9e: newCBInstruction("RES 3,(HL)", 2, 16, res3hl),
+		0x9f: newCBInstruction("RES 3,A", 2, 8, res3A),
+
+		0xa0: newCBInstruction("RES 4,B", 2, 8, res4B),
+		0xa1: newCBInstruction("RES 4,C", 2, 8, res4C),
+		0xa2: newCBInstruction("RES 4,D", 2, 8, res4D),
+		0xa3: newCBInstruction("RES 4,E", 2, 8, res4E),
+		0xa4: newCBInstruction("RES 4,H", 2, 8, res4H),
+		0xa5: newCBInstruction("RES 4,L", 2, 8, res4L),
+		0xa6: newCBInstruction("RES 4,(HL)", 2, 16, res4hl),
+		0xa7: newCBInstruction("RES 4,A", 2, 8, res4A),
+		0xa8: newCBInstruction("RES 5,B", 2, 8, res5B),
+		0xa9: newCBInstruction("RES 5,C", 2, 8, res5C),
+		0xaa: newCBInstruction("RES 5,D", 2, 8, res5D),
+		0xab: newCBInstruction("RES 5,E", 2, 8, res5E),
+		0xac: newCBInstruction("RES 5,H", 2, 8, res5H),
+		0xad: newCBInstruction("RES 5,L", 2, 8, res5L),
 		0xae: newCBInstruction("RES 5,(HL)", 2, 16, res5hl),
-
+		0xaf: newCBInstruction("RES 5,A", 2, 8, res5A),
+
+		0xb0: newCBInstruction("RES 6,B", 2, 8, res6B),
+		0xb1: newCBInstruction("RES 6,C", 2, 8, res6C),
+		0xb2: newCBInstruction("RES 6,D", 2, 8, res6D),
+		0xb3: newCBInstruction("RES 6,E", 2, 8, res6E),
+		0xb4: newCBInstruction("RES 6,H", 2, 8, res6H),
+		0xb5: newCBInstruction("RES 6,L", 2, 8, res6L),
 		0xb6: newCBInstruction("RES 6,(HL)", 2, 16, res6hl),
-		0xbe: newCBInstruction("RES 7,(HL)", 2, 8, res7hl),
-
+		0xb7: newCBInstruction("RES 6,A", 2, 8, res6A),
+		0xb8: newCBInstruction("RES 7,B", 2, 8, res7B),
+		0xb9: newCBInstruction("RES 7,C", 2, 8, res7C),
+		0xba: newCBInstruction("RES 7,D", 2, 8, res7D),
+		0xbb: newCBInstruction("RES 7,E", 2, 8, res7E),
+		0xbc: newCBInstruction("RES 7,H", 2, 8, res7H),
+		0xbd: newCBInstruction("RES 7,L", 2, 8, res7L),
+		0xbe: newCBInstruction("RES 7,(HL)", 2, 16, res7hl),
+		0xbf: newCBInstruction("RES 7,A", 2, 8, res7A),
+
+		0xc0: newCBInstruction("SET 0,B", 2, 8, set0B),
+		0xc1: newCBInstruction("SET 0,C", 2, 8, set0C),
+		0xc2: newCBInstruction("SET 0,D", 2, 8, set0D),
+		0xc3: newCBInstruction("SET 0,E", 2, 8, set0E),
+		0xc4: newCBInstruction("SET 0,H", 2, 8, set0H),
+		0xc5: newCBInstruction("SET 0,L", 2, 8, set0L),
 		0xc6: newCBInstruction("SET 0,(HL)", 2, 16, set0hl),
 		0xc7: newCBInstruction("SET 0,A", 2, 8, set0A),
+		0xc8: newCBInstruction("SET 1,B", 2, 8, set1B),
+		0xc9: newCBInstruction("SET 1,C", 2, 8, set1C),
+		0xca: newCBInstruction("SET 1,D", 2, 8, set1D),
+		0xcb: newCBInstruction("SET 1,E", 2, 8, set1E),
+		0xcc: newCBInstruction("SET 1,H", 2, 8, set1H),
+		0xcd: newCBInstruction("SET 1,L", 2, 8, set1L),
 		0xce: newCBInstruction("SET 1,(HL)", 2, 16, set1hl),
 		0xcf: newCBInstruction("SET 1,A", 2, 8, set1A),
 
+		0xd0: newCBInstruction("SET 2,B", 2, 8, set2B),
+		0xd1: newCBInstruction("SET 2,C", 2, 8, set2C),
+		0xd2: newCBInstruction("SET 2,D", 2, 8, set2D),
+		0xd3: newCBInstruction("SET 2,E", 2, 8, set2E),
+		0xd4: newCBInstruction("SET 2,H", 2, 8, set2H),
+		0xd5: newCBInstruction("SET 2,L", 2, 8, set2L),
 		0xd6: newCBInstruction("SET 2,(HL)", 2, 16, set2hl),
 		0xd7: newCBInstruction("SET 2,A", 2, 8, set2A),
+		0xd8: newCBInstruction("SET 3,B", 2, 8, set3B),
+		0xd9: newCBInstruction("SET 3,C", 2, 8, set3C),
+		0xda: newCBInstruction("SET 3,D", 2, 8, set3D),
+		0xdb: newCBInstruction("SET 3,E", 2, 8, set3E),
+		0xdc: newCBInstruction("SET 3,H", 2, 8, set3H),
+		0xdd: newCBInstruction("SET 3,L", 2, 8, set3L),
 		0xde: newCBInstruction("SET 3,(HL)", 2, 16, set3hl),
-
+		0xdf: newCBInstruction("SET 3,A", 2, 8, set3A),
+
+		0xe0: newCBInstruction("SET 4,B", 2, 8, set4B),
+		0xe1: newCBInstruction("SET 4,C", 2, 8, set4C),
+		0xe2: newCBInstruction("SET 4,D", 2, 8, set4D),
+		0xe3: newCBInstruction("SET 4,E", 2, 8, set4E),
+		0xe4: newCBInstruction("SET 4,H", 2, 8, set4H),
+		0xe5: newCBInstruction("SET 4,L", 2, 8, set4L),
+		0xe6: newCBInstruction("SET 4,(HL)", 2, 16, set4hl),
 		0xe7: newCBInstruction("SET 4,A", 2, 8, set4A),
+		0xe8: newCBInstruction("SET 5,B", 2, 8, set5B),
+		0xe9: newCBInstruction("SET 5,C", 2, 8, set5C),
+		0xea: newCBInstruction("SET 5,D", 2, 8, set5D),
+		0xeb: newCBInstruction("SET 5,E", 2, 8, set5E),
+		0xec: newCBInstruction("SET 5,H", 2, 8, set5H),
+		0xed: newCBInstruction("SET 5,L", 2, 8, set5L),
 		0xee: newCBInstruction("SET 5,(HL)", 2, 16, set5hl),
-
+		0xef: newCBInstruction("SET 5,A", 2, 8, set5A),
+
+		0xf0: newCBInstruction("SET 6,B", 2, 8, set6B),
+		0xf1: newCBInstruction("SET 6,C", 2, 8, set6C),
+		0xf2: newCBInstruction("SET 6,D", 2, 8, set6D),
+		0xf3: newCBInstruction("SET 6,E", 2, 8, set6E),
+		0xf4: newCBInstruction("SET 6,H", 2, 8, set6H),
+		0xf5: newCBInstruction("SET 6,L", 2, 8, set6L),
+		0xf6: newCBInstruction("SET 6,(HL)", 2, 16, set6hl),
 		0xf7: newCBInstruction("SET 6,A", 2, 8, set6A),
-		0xfe: newCBInstruction("SET 7,(HL)", 2, 16, set_7_hl),
+		0xf8: newCBInstruction("SET 7,B", 2, 8, set7B),
+		0xf9: newCBInstruction("SET 7,C", 2, 8, set7C),
+		0xfa: newCBInstruction("SET 7,D", 2, 8, set7D),
+		0xfb: newCBInstruction("SET 7,E", 2, 8, set7E),
+		0xfc: newCBInstruction("SET 7,H", 2, 8, set7H),
+		0xfd: newCBInstruction("SET 7,L", 2, 8, set7L),
+		0xfe: newCBInstruction("SET 7,(HL)", 2, 16, set7hl),
+		0xff: newCBInstruction("SET 7,A", 2, 8, set7A),
 	}
 }
 
-func newCBInstruction(name string, length int, duration int, fp func(mem *memory, reg *register, cbInstr *cbInstruction) int) *cbInstruction {
+func newCBInstruction(name string, length int, duration int, fp cbInstructionExecutor) *cbInstruction {
 	return newCBConditionalInstruction(name, length, duration, 0, fp)
 }
 
-func newCBConditionalInstruction(name string, length int, actionDuration int, noopDuration int, fp func(mem *memory, reg *register, cbInstr *cbInstruction) int) *cbInstruction {
+func newCBConditionalInstruction(name string, length int, actionDuration int, noopDuration int, fp cbInstructionExecutor) *cbInstruction {
 	return &cbInstruction{
 		name:           name,
 		bytes:          length,
@@ -106,486 +315,1672 @@ func newCBConditionalInstruction(name string, length int, actionDuration int, no
 	}
 }
 
-func bit_7_h(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(7, reg.H)
+// Sets the flags shared by all rotate, shift and swap instructions and returns the result
+func shiftFlags(reg *register, result uint8, carry bool) uint8 {
+	reg.setZ(result == 0)
+	reg.setN(false)
+	reg.setH(false)
+	reg.setC(carry)
+	return result
+}
+
+func rotateLeftCircular(reg *register, val uint8) uint8 {
+	result, carry := rLeft(val)
+	return shiftFlags(reg, result, carry)
+}
+
+func rotateRightCircular(reg *register, val uint8) uint8 {
+	result, carry := rRight(val)
+	return shiftFlags(reg, result, carry)
+}
 
+func rotateLeft(reg *register, val uint8) uint8 {
+	result, carry := rLeftCarry(val, reg.isC())
+	return shiftFlags(reg, result, carry)
+}
+
+func rotateRight(reg *register, val uint8) uint8 {
+	result, carry := rRightCarry(val, reg.isC())
+	return shiftFlags(reg, result, carry)
+}
+
+func shiftLeftArithmetic(reg *register, val uint8) uint8 {
+	return shiftFlags(reg, val<<1, testBit(val, 7))
+}
+
+// Shifts right, keeping the sign bit
+func shiftRightArithmetic(reg *register, val uint8) uint8 {
+	return shiftFlags(reg, val>>1|val&0x80, testBit(val, 0))
+}
+
+func shiftRightLogical(reg *register, val uint8) uint8 {
+	return shiftFlags(reg, val>>1, testBit(val, 0))
+}
+
+func swapNibbles(reg *register, val uint8) uint8 {
+	return shiftFlags(reg, val<<4|val>>4, false)
+}
+
+func rlcB(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = rotateLeftCircular(reg, reg.B)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_7_l(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(7, reg.L)
+func rlcC(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = rotateLeftCircular(reg, reg.C)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rlcD(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = rotateLeftCircular(reg, reg.D)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_0_a(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(0, reg.A)
+func rlcE(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = rotateLeftCircular(reg, reg.E)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rlcH(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = rotateLeftCircular(reg, reg.H)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_0_b(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(0, reg.B)
+func rlcL(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = rotateLeftCircular(reg, reg.L)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rlcHL(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, rotateLeftCircular(reg, mem.read8(address)))
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_0_c(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(0, reg.C)
+func rlcA(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = rotateLeftCircular(reg, reg.A)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rrcB(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = rotateRightCircular(reg, reg.B)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_1_b(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(1, reg.B)
+func rrcC(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = rotateRightCircular(reg, reg.C)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rrcD(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = rotateRightCircular(reg, reg.D)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_1_hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(1, mem.read8(reg.readDuo(REG_HL)))
+func rrcE(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = rotateRightCircular(reg, reg.E)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rrcH(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = rotateRightCircular(reg, reg.H)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_1_a(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(1, reg.A)
+func rrcL(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = rotateRightCircular(reg, reg.L)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rrcHL(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, rotateRightCircular(reg, mem.read8(address)))
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_2_a(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(2, reg.A)
+func rrcA(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = rotateRightCircular(reg, reg.A)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rlB(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = rotateLeft(reg, reg.B)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_2_b(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(2, reg.B)
+func rlC(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = rotateLeft(reg, reg.C)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rlD(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = rotateLeft(reg, reg.D)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_3_a(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(3, reg.A)
+func rlE(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = rotateLeft(reg, reg.E)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rlH(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = rotateLeft(reg, reg.H)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_3_b(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(3, reg.B)
+func rlL(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = rotateLeft(reg, reg.L)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rlHL(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, rotateLeft(reg, mem.read8(address)))
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_4_b(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(4, reg.B)
+func rlA(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = rotateLeft(reg, reg.A)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rrB(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = rotateRight(reg, reg.B)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_4_c(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(4, reg.C)
+func rrC(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = rotateRight(reg, reg.C)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rrD(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = rotateRight(reg, reg.D)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_5_a(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(5, reg.A)
+func rrE(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = rotateRight(reg, reg.E)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rrH(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = rotateRight(reg, reg.H)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_6_b(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(6, reg.B)
+func rrL(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = rotateRight(reg, reg.L)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func rrHL(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, rotateRight(reg, mem.read8(address)))
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_6_c(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(6, reg.B)
+func rrA(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = rotateRight(reg, reg.A)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func slaB(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = shiftLeftArithmetic(reg, reg.B)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_6_h(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(6, reg.H)
+func slaC(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = shiftLeftArithmetic(reg, reg.C)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func slaD(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = shiftLeftArithmetic(reg, reg.D)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_6_hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(6, mem.read8(reg.readDuo(REG_HL)))
+func slaE(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = shiftLeftArithmetic(reg, reg.E)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func slaH(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = shiftLeftArithmetic(reg, reg.H)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_5_b(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(5, reg.B)
+func slaL(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = shiftLeftArithmetic(reg, reg.L)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func slaHL(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, shiftLeftArithmetic(reg, mem.read8(address)))
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_5_c(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(5, reg.C)
+func slaA(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = shiftLeftArithmetic(reg, reg.A)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func sraB(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = shiftRightArithmetic(reg, reg.B)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_5_d(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(5, reg.D)
+func sraC(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = shiftRightArithmetic(reg, reg.C)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func sraD(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = shiftRightArithmetic(reg, reg.D)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_6_a(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(6, reg.A)
+func sraE(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = shiftRightArithmetic(reg, reg.E)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func sraH(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = shiftRightArithmetic(reg, reg.H)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_7_b(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(7, reg.B)
+func sraL(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = shiftRightArithmetic(reg, reg.L)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func sraHL(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, shiftRightArithmetic(reg, mem.read8(address)))
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_7_c(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(7, reg.C)
+func sraA(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = shiftRightArithmetic(reg, reg.A)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func swapB(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = swapNibbles(reg, reg.B)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_7_d(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(7, reg.D)
+func swapC(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = swapNibbles(reg, reg.C)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func swapD(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = swapNibbles(reg, reg.D)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_7_e(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(7, reg.E)
+func swapE(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = swapNibbles(reg, reg.E)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func swapH(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = swapNibbles(reg, reg.H)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_7_a(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(7, reg.A)
+func swapL(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = swapNibbles(reg, reg.L)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func swapHL(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, swapNibbles(reg, mem.read8(address)))
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func bit_7_hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.bit(7, mem.read8(reg.readDuo(REG_HL)))
+func swapA(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = swapNibbles(reg, reg.A)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
+func srlB(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = shiftRightLogical(reg, reg.B)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func rl_c(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	isCarrySet := reg.isC()
-	isMSBSet := testBit(reg.C, 7)
+func srlC(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = shiftRightLogical(reg, reg.C)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
-	reg.setZ( false)
-	reg.setN( false)
-	reg.setH( false)
+func srlD(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = shiftRightLogical(reg, reg.D)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
-	newVal := reg.C << 1
+func srlE(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = shiftRightLogical(reg, reg.E)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
-	reg.setC( isMSBSet)
+func srlH(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = shiftRightLogical(reg, reg.H)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
-	if isCarrySet {
-		newVal = setBit(newVal, 0)
-	}
+func srlL(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = shiftRightLogical(reg, reg.L)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
-	if newVal == 0 {
-		reg.setZ( true)
-	}
-	reg.C = newVal
+func srlHL(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, shiftRightLogical(reg, mem.read8(address)))
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func res0A(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.A = resetBit(reg.A, 0)
+func srlA(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = shiftRightLogical(reg, reg.A)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func res0hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	mem.write8(reg.readDuo(REG_HL), resetBit(mem.read8(reg.readDuo(REG_HL)), 0))
+func bit_0_b(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(0, reg.B)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func res1hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	mem.write8(reg.readDuo(REG_HL), resetBit(mem.read8(reg.readDuo(REG_HL)), 1))
+func bit_0_c(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(0, reg.C)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func res2hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	mem.write8(reg.readDuo(REG_HL), resetBit(mem.read8(reg.readDuo(REG_HL)), 2))
+func bit_0_d(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(0, reg.D)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func res3hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	mem.write8(reg.readDuo(REG_HL), resetBit(mem.read8(reg.readDuo(REG_HL)), 3))
+func bit_0_e(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(0, reg.E)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func res5hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	mem.write8(reg.readDuo(REG_HL), resetBit(mem.read8(reg.readDuo(REG_HL)), 5))
+func bit_0_h(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(0, reg.H)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func res6hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	mem.write8(reg.readDuo(REG_HL), resetBit(mem.read8(reg.readDuo(REG_HL)), 6))
+func bit_0_l(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(0, reg.L)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func res7hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	mem.write8(reg.readDuo(REG_HL), resetBit(mem.read8(reg.readDuo(REG_HL)), 7))
+func bit_0_hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(0, mem.read8(reg.readDuo(REG_HL)))
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func swapA(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	val := reg.A
+func bit_0_a(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(0, reg.A)
 
-	reg.A = val<<4 | val>>4
-	reg.setZ( reg.A == 0)
-	reg.setN( false)
-	reg.setH( false)
-	reg.setC( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func swapB(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	val := reg.B
+func bit_1_b(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(1, reg.B)
 
-	reg.B = val<<4 | val>>4
-	reg.setZ( reg.B == 0)
-	reg.setN( false)
-	reg.setH( false)
-	reg.setC( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func swapE(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	val := reg.E
+func bit_1_c(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(1, reg.C)
 
-	reg.E = val<<4 | val>>4
-	reg.setZ( reg.E == 0)
-	reg.setN( false)
-	reg.setH( false)
-	reg.setC( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func slaHL(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	val := mem.read8(reg.readDuo(REG_HL))
-	reg.setC( val>>7 == 1)
-	reg.A = val << 1
+func bit_1_d(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(1, reg.D)
 
-	reg.setZ( reg.A == 0)
-	reg.setN( false)
-	reg.setH( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func slaA(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.setC( reg.A>>7 == 1)
-	reg.A = reg.A << 1
+func bit_1_e(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(1, reg.E)
 
-	reg.setZ( reg.A == 0)
-	reg.setN( false)
-	reg.setH( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set1hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	mem.write8(reg.readDuo(REG_HL), setBit(mem.read8(reg.readDuo(REG_HL)), 1))
+func bit_1_h(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(1, reg.H)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set0A(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.A = setBit(reg.A, 0)
+func bit_1_l(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(1, reg.L)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set1A(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.A = setBit(reg.A, 1)
+func bit_1_hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(1, mem.read8(reg.readDuo(REG_HL)))
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set2A(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.A = setBit(reg.A, 2)
+func bit_1_a(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(1, reg.A)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set4A(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.A = setBit(reg.A, 4)
+func bit_2_b(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(2, reg.B)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set6A(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.A = setBit(reg.A, 6)
+func bit_2_c(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(2, reg.C)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set_7_hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	address := reg.readDuo(REG_HL)
-	val := mem.read8(address)
-	mem.write8(address, setBit(val, 7))
+func bit_2_d(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(2, reg.D)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set0hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	address := reg.readDuo(REG_HL)
-	val := mem.read8(address)
-	mem.write8(address, setBit(val, 0))
+func bit_2_e(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(2, reg.E)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set2hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	address := reg.readDuo(REG_HL)
-	val := mem.read8(address)
-	mem.write8(address, setBit(val, 2))
+func bit_2_h(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(2, reg.H)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set3hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	address := reg.readDuo(REG_HL)
-	val := mem.read8(address)
-	mem.write8(address, setBit(val, 3))
+func bit_2_l(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(2, reg.L)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func set5hl(mem *memory, reg *register, cbInstr *cbInstruction) int {
-	address := reg.readDuo(REG_HL)
-	val := mem.read8(address)
-	mem.write8(address, setBit(val, 5))
+func bit_2_hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(2, mem.read8(reg.readDuo(REG_HL)))
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_2_a(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(2, reg.A)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_3_b(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(3, reg.B)
+
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func srlA(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.setC( reg.A&0x1 == 1)
-	reg.A >>= 1
+func bit_3_c(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(3, reg.C)
 
-	reg.setZ( reg.A == 0)
-	reg.setN( false)
-	reg.setH( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func srlB(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.setC( reg.B&0x1 == 1)
-	reg.B >>= 1
+func bit_3_d(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(3, reg.D)
 
-	reg.setZ( reg.B == 0)
-	reg.setN( false)
-	reg.setH( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func srlD(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.setC( reg.D&0x1 == 1)
-	reg.D >>= 1
+func bit_3_e(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(3, reg.E)
 
-	reg.setZ( reg.D == 0)
-	reg.setN( false)
-	reg.setH( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
+func bit_3_h(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(3, reg.H)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
 
-func srlE(_ *memory, reg *register, cbInstr *cbInstruction) int {
-	reg.setC( reg.E&0x1 == 1)
-	reg.E >>= 1
+func bit_3_l(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(3, reg.L)
 
-	reg.setZ( reg.E == 0)
-	reg.setN( false)
-	reg.setH( false)
 	reg.incPC(cbInstr.bytes)
-	return cbInstr.actionDuration
+	return true
 }
 
-func rrC(_ *memory, reg *register, _ *cbInstruction) int {
-	return rotateRight(&reg.C, reg)
+func bit_3_hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(3, mem.read8(reg.readDuo(REG_HL)))
+
+	reg.incPC(cbInstr.bytes)
+	return true
 }
 
-func rrD(_ *memory, reg *register, _ *cbInstruction) int {
-	return rotateRight(&reg.D, reg)
+func bit_3_a(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(3, reg.A)
+
+	reg.incPC(cbInstr.bytes)
+	return true
 }
 
-func rrE(_ *memory, reg *register, _ *cbInstruction) int {
-	return rotateRight(&reg.E, reg)
+func bit_4_b(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(4, reg.B)
+
+	reg.incPC(cbInstr.bytes)
+	return true
 }
 
-func rotateRight(r *uint8, reg *register) int {
-	val := *r
-	carry := reg.isC()
+func bit_4_c(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(4, reg.C)
 
-	reg.C = val >> 1
-	if carry {
-		setBit(reg.C, 7)
-	}
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_4_d(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(4, reg.D)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_4_e(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(4, reg.E)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_4_h(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(4, reg.H)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_4_l(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(4, reg.L)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_4_hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(4, mem.read8(reg.readDuo(REG_HL)))
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_4_a(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(4, reg.A)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_5_b(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(5, reg.B)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_5_c(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(5, reg.C)
 
-	reg.setZ( reg.C == 0)
-	reg.setN( false)
-	reg.setH( false)
-	reg.setC( reg.C&0x1 == 1)
-	reg.incPC(2)
-	return 8
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_5_d(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(5, reg.D)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_5_e(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(5, reg.E)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_5_h(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(5, reg.H)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_5_l(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(5, reg.L)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_5_hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(5, mem.read8(reg.readDuo(REG_HL)))
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_5_a(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(5, reg.A)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_6_b(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(6, reg.B)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_6_c(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(6, reg.C)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_6_d(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(6, reg.D)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_6_e(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(6, reg.E)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_6_h(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(6, reg.H)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_6_l(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(6, reg.L)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_6_hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(6, mem.read8(reg.readDuo(REG_HL)))
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_6_a(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(6, reg.A)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_7_b(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(7, reg.B)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_7_c(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(7, reg.C)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_7_d(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(7, reg.D)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_7_e(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(7, reg.E)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_7_h(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(7, reg.H)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_7_l(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(7, reg.L)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_7_hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(7, mem.read8(reg.readDuo(REG_HL)))
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func bit_7_a(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.bit(7, reg.A)
+
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res0B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = resetBit(reg.B, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res0C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = resetBit(reg.C, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res0D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = resetBit(reg.D, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res0E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = resetBit(reg.E, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res0H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = resetBit(reg.H, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res0L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = resetBit(reg.L, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res0hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, resetBit(mem.read8(address), 0))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res0A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = resetBit(reg.A, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res1B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = resetBit(reg.B, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res1C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = resetBit(reg.C, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res1D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = resetBit(reg.D, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res1E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = resetBit(reg.E, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res1H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = resetBit(reg.H, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res1L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = resetBit(reg.L, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res1hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, resetBit(mem.read8(address), 1))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res1A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = resetBit(reg.A, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res2B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = resetBit(reg.B, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res2C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = resetBit(reg.C, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res2D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = resetBit(reg.D, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res2E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = resetBit(reg.E, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res2H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = resetBit(reg.H, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res2L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = resetBit(reg.L, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res2hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, resetBit(mem.read8(address), 2))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res2A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = resetBit(reg.A, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res3B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = resetBit(reg.B, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res3C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = resetBit(reg.C, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res3D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = resetBit(reg.D, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res3E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = resetBit(reg.E, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res3H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = resetBit(reg.H, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res3L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = resetBit(reg.L, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res3hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, resetBit(mem.read8(address), 3))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res3A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = resetBit(reg.A, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res4B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = resetBit(reg.B, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res4C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = resetBit(reg.C, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res4D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = resetBit(reg.D, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res4E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = resetBit(reg.E, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res4H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = resetBit(reg.H, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res4L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = resetBit(reg.L, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res4hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, resetBit(mem.read8(address), 4))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res4A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = resetBit(reg.A, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res5B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = resetBit(reg.B, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res5C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = resetBit(reg.C, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res5D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = resetBit(reg.D, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res5E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = resetBit(reg.E, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res5H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = resetBit(reg.H, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res5L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = resetBit(reg.L, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res5hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, resetBit(mem.read8(address), 5))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res5A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = resetBit(reg.A, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res6B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = resetBit(reg.B, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res6C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = resetBit(reg.C, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res6D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = resetBit(reg.D, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res6E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = resetBit(reg.E, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res6H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = resetBit(reg.H, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res6L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = resetBit(reg.L, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res6hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, resetBit(mem.read8(address), 6))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res6A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = resetBit(reg.A, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res7B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = resetBit(reg.B, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res7C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = resetBit(reg.C, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res7D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = resetBit(reg.D, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res7E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = resetBit(reg.E, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res7H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = resetBit(reg.H, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res7L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = resetBit(reg.L, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res7hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, resetBit(mem.read8(address), 7))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func res7A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = resetBit(reg.A, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set0B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = setBit(reg.B, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set0C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = setBit(reg.C, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set0D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = setBit(reg.D, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set0E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = setBit(reg.E, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set0H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = setBit(reg.H, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set0L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = setBit(reg.L, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set0hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, setBit(mem.read8(address), 0))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set0A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = setBit(reg.A, 0)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set1B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = setBit(reg.B, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set1C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = setBit(reg.C, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set1D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = setBit(reg.D, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set1E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = setBit(reg.E, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set1H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = setBit(reg.H, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set1L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = setBit(reg.L, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set1hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, setBit(mem.read8(address), 1))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set1A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = setBit(reg.A, 1)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set2B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = setBit(reg.B, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set2C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = setBit(reg.C, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set2D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = setBit(reg.D, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set2E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = setBit(reg.E, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set2H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = setBit(reg.H, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set2L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = setBit(reg.L, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set2hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, setBit(mem.read8(address), 2))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set2A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = setBit(reg.A, 2)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set3B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = setBit(reg.B, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set3C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = setBit(reg.C, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set3D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = setBit(reg.D, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set3E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = setBit(reg.E, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set3H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = setBit(reg.H, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set3L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = setBit(reg.L, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set3hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, setBit(mem.read8(address), 3))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set3A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = setBit(reg.A, 3)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set4B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = setBit(reg.B, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set4C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = setBit(reg.C, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set4D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = setBit(reg.D, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set4E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = setBit(reg.E, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set4H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = setBit(reg.H, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set4L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = setBit(reg.L, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set4hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, setBit(mem.read8(address), 4))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set4A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = setBit(reg.A, 4)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set5B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = setBit(reg.B, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set5C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = setBit(reg.C, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set5D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = setBit(reg.D, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set5E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = setBit(reg.E, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set5H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = setBit(reg.H, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set5L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = setBit(reg.L, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set5hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, setBit(mem.read8(address), 5))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set5A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = setBit(reg.A, 5)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set6B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = setBit(reg.B, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set6C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = setBit(reg.C, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set6D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = setBit(reg.D, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set6E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = setBit(reg.E, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set6H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = setBit(reg.H, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set6L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = setBit(reg.L, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set6hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, setBit(mem.read8(address), 6))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set6A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = setBit(reg.A, 6)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set7B(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.B = setBit(reg.B, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set7C(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.C = setBit(reg.C, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set7D(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.D = setBit(reg.D, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set7E(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.E = setBit(reg.E, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set7H(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.H = setBit(reg.H, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set7L(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.L = setBit(reg.L, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set7hl(mem *memory, reg *register, cbInstr *cbInstruction) bool {
+	address := reg.readDuo(REG_HL)
+	mem.write8(address, setBit(mem.read8(address), 7))
+	reg.incPC(cbInstr.bytes)
+	return true
+}
+
+func set7A(_ *memory, reg *register, cbInstr *cbInstruction) bool {
+	reg.A = setBit(reg.A, 7)
+	reg.incPC(cbInstr.bytes)
+	return true
 }
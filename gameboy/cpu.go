@@ -2,74 +2,74 @@ package gameboy
 
 import (
 	"fmt"
-	"github.com/davecgh/go-spew/spew"
 )
 
-var _ = spew.Config
+/*
+Executes one instruction, or waits one M-cycle while the CPU is halted, stopped or locked up. Returns
+a *Fault when the game used hardware that is not emulated, the emulation can continue after it.
+*/
+func (gb *Gameboy) Step() error {
+	pc := gb.reg.PC
+	gb.step()
+
+	if fault := gb.mem.fault; fault != nil {
+		gb.mem.fault = nil
+		fault.PC = pc
+		return fault
+	}
+	return nil
+}
 
-func (gb *Gameboy) Step() {
-	if gb.halted {
-		gb.updateTimer(4)
-		gb.graphics.updateGraphics(4)
-		if gb.handleInterrupts() {
-			gb.halted = false
+func (gb *Gameboy) step() {
+	if gb.reg.locked {
+		gb.tick(4)
+		return
+	}
+
+	if gb.reg.halted {
+		gb.tick(4)
+		// A pending interrupt always ends the HALT. It is only serviced when interrupts are enabled,
+		// otherwise execution simply continues after the HALT instruction.
+		if gb.mem.interruptPending() {
+			gb.reg.halted = false
+			gb.handleInterrupts()
 		}
 
 		return
 	}
 
-	if gb.stopped {
-		gb.updateTimer(4)
-		if gb.mem.ioPorts[0x00] & 0xf < 0xf {
-			gb.stopped = false
+	if gb.reg.stopped {
+		// The PPU keeps finishing frames, so RunFrame returns and the frontend can pass on the buttons
+		gb.tick(4)
+		// A pressed button on a selected line ends the STOP
+		if gb.mem.joypad()&0xf != 0xf {
+			gb.reg.stopped = false
 		}
 		return
 	}
 
 	oldPC := gb.reg.PC
 
-	instrLength, name := gb.executeInstruction()
+	// EI takes effect after the instruction following it
+	enableInterrupts := gb.reg.interruptEnableScheduled
+
+	instrLength := gb.executeInstruction()
 
-	if gb.interruptEnableScheduled {
+	if enableInterrupts && gb.reg.interruptEnableScheduled {
 		if gb.options.Debug {
 			fmt.Printf("%#04x: Enable interrupts\n", oldPC)
 		}
-		gb.interruptEnableScheduled = false
-		gb.interruptMaster = true
-	} else if gb.interruptDisableScheduled {
-		gb.interruptDisableScheduled = false
-		gb.interruptMaster = false
-		if gb.options.Debug {
-			fmt.Printf("%#04x: Disable interrupts\n", oldPC)
-		}
+		gb.reg.interruptEnableScheduled = false
+		gb.reg.interruptMaster = true
 	}
 
-	// TODO: Move to respective instructions
-	if name == "DI" {
-		gb.interruptDisableScheduled = true
-		if gb.options.Debug {
-			fmt.Println("Scheduling disable interrupts")
-		}
-	} else if name == "EI" {
-		gb.interruptEnableScheduled = true
-		if gb.options.Debug {
-			fmt.Println("Scheduling enabling interrupts")
-		}
-	} else if name == "RETI" {
-		gb.interruptMaster = true
-		if gb.options.Debug {
-			fmt.Println("Return enabling interrupts")
-		}
-	} else if name == "HALT" {
-		gb.halted = true
-	} else if name == "STOP 0" {
-		gb.stopped = true
+	if gb.reg.locked && gb.options.Debug {
+		fmt.Printf("%#04x: Illegal instruction, locking up\n", oldPC)
 	}
 
-	gb.updateTimer(instrLength)
-	gb.graphics.updateGraphics(instrLength)
+	gb.finishCycles(instrLength)
 	if gb.handleInterrupts() {
-		gb.halted = false
+		gb.reg.halted = false
 	}
 
 	// Swap out the boot rom
@@ -83,136 +83,66 @@ func (gb *Gameboy) PC() uint16 {
 	return gb.reg.PC
 }
 
-func (gb *Gameboy) updateTimer(cycles int) {
-	gb.timer.t += cycles
-
-	if gb.timer.t >= 16 {
-		gb.timer.m++
-		gb.timer.t -= 16
-		gb.timer.d++
-		if gb.timer.d == 16 {
-			gb.timer.d = 0
-			val := gb.mem.ioPorts[0x04]
-			gb.mem.ioPorts[0x04] = val + 1
-		}
-	}
-
-	tac := gb.mem.ioPorts[0x07]
-
-	// If the timer is turned on,
-	t := 0
-	if testBit(tac, 2) {
-		val := tac & 0x3
-		switch val {
-		case 0:
-			t = 64
-		case 1:
-			t = 1
-		case 2:
-			t = 4
-		case 3:
-			t = 16
-		}
-
-		if gb.timer.m >= t {
-			gb.timer.m = 0
-			gb.mem.ioPorts[0x05]++
-
-			if gb.mem.ioPorts[0x05] == 0 {
-				gb.mem.ioPorts[0x05] = gb.mem.ioPorts[0x06]
-				gb.mem.ioPorts[0x0F] |= 0x4
-			}
-		}
-	}
+// Advances the timer, the PPU and the OAM DMA by the given number of cycles
+func (gb *Gameboy) tick(cycles int) {
+	gb.cycles += uint64(cycles)
+	gb.updateTimer(cycles)
+	gb.mem.mbc.tick(cycles)
+	gb.mem.updateDMA(cycles)
+	gb.graphics.updateGraphics(cycles)
 }
 
-func (gb *Gameboy) HandleInput(input *Input) bool {
-	joyPadReg := gb.mem.ioPorts[0x00]
-	if !testBit(joyPadReg, 4) && !testBit(joyPadReg, 5) {
-		// Do nothing when input is not polled
-		return false
-	} else if testBit(joyPadReg, 4) && testBit(joyPadReg, 5) {
-		// Reset the register
-		gb.mem.write8(0xFF00, 0xf)
-		return false
+/*
+Advances the hardware by the cycles of an instruction or interrupt dispatch. In M-cycle mode the
+memory accesses already ticked their own cycle, only the remaining internal cycles are ticked here.
+*/
+func (gb *Gameboy) finishCycles(cycles int) {
+	if gb.mem.clock != nil {
+		cycles -= gb.mem.clock.ticked
+		gb.mem.clock.ticked = 0
 	}
+	gb.tick(cycles)
+}
 
-	if testBit(joyPadReg, 4) {
-		if input.ENTER {
-			joyPadReg = resetBit(joyPadReg, 3)
-		} else {
-			joyPadReg = setBit(joyPadReg, 3)
-		}
-		if input.SPACE {
-			joyPadReg = resetBit(joyPadReg, 2)
-		} else {
-			joyPadReg = setBit(joyPadReg, 2)
-		}
-		if input.B {
-			joyPadReg = resetBit(joyPadReg, 1)
-		} else {
-			joyPadReg = setBit(joyPadReg, 1)
-		}
-		if input.A {
-			joyPadReg = resetBit(joyPadReg, 0)
-		} else {
-			joyPadReg = setBit(joyPadReg, 0)
-		}
-	} else if testBit(joyPadReg, 5) {
-		if input.DOWN {
-			joyPadReg = resetBit(joyPadReg, 3)
-		} else {
-			joyPadReg = setBit(joyPadReg, 3)
-		}
-		if input.UP {
-			joyPadReg = resetBit(joyPadReg, 2)
-		} else {
-			joyPadReg = setBit(joyPadReg, 2)
-		}
-		if input.LEFT {
-			joyPadReg = resetBit(joyPadReg, 1)
-		} else {
-			joyPadReg = setBit(joyPadReg, 1)
-		}
-		if input.RIGHT {
-			joyPadReg = resetBit(joyPadReg, 0)
-		} else {
-			joyPadReg = setBit(joyPadReg, 0)
-		}
+func (gb *Gameboy) updateTimer(cycles int) {
+	if gb.mem.timer.tick(cycles) {
+		gb.mem.ioPorts[0x0F] |= 0x4
 	}
-
-	joyPadReg = resetBit(joyPadReg, 4)
-	joyPadReg = resetBit(joyPadReg, 5)
-
-	gb.mem.ioPorts[0x00] = joyPadReg
-
-	return joyPadReg < 0xf
 }
 
+/*
+Services the highest priority interrupt that is both requested and enabled, if interrupts are
+enabled. Requests that are not serviced stay in IF. Returns whether an interrupt was serviced.
+*/
 func (gb *Gameboy) handleInterrupts() bool {
-	if !gb.interruptMaster {
-		gb.mem.write8(0xFF0F, 0x0)
+	if !gb.reg.interruptMaster {
 		return false
 	}
-	req := gb.mem.read8(0xFF0F)
-	enabled := gb.mem.read8(0xFFFF)
-	handled := false
-	if req > 0 {
-		for i := 0; i < 5; i += 1 {
-			if testBit(req, uint(i)) && testBit(enabled, uint(i)) {
-				gb.serviceInterrupt(i, req)
-				handled = true
-			}
+	pending := gb.mem.ioPorts[0x0F] & gb.mem.interruptEnableRegister & 0x1F
+	for i := 0; i < 5; i += 1 {
+		if testBit(pending, uint(i)) {
+			gb.serviceInterrupt(i)
+			return true
 		}
 	}
-	return handled
+	return false
 }
 
-func (gb *Gameboy) serviceInterrupt(i int, requested uint8) {
-	gb.mem.write8(0xff0f, resetBit(requested, uint(i)))
-	pushStack16(gb.mem, gb.reg, gb.reg.PC)
+// Dispatching an interrupt takes 20 cycles: two wait states, pushing the PC and the jump
+const interruptDuration = 20
+
+func (gb *Gameboy) serviceInterrupt(i int) {
+	gb.reg.interruptMaster = false
+	gb.mem.ioPorts[0x0F] = resetBit(gb.mem.ioPorts[0x0F], uint(i))
+	gb.mem.depth++
+	returnAddress := gb.reg.PC
+	if gb.reg.haltBug {
+		// EI followed by HALT with a pending interrupt: the interrupt returns to the HALT, which runs again
+		gb.reg.haltBug = false
+		returnAddress--
+	}
+	pushStack16(gb.mem, gb.reg, returnAddress)
 
-	// TODO: Implement serial transfer
 	switch i {
 	case 0:
 		if gb.options.Debug {
@@ -229,73 +159,79 @@ func (gb *Gameboy) serviceInterrupt(i int, requested uint8) {
 			fmt.Println("Servicing timer overflow interrupt")
 		}
 		gb.reg.PC = 0x50
+	case 3:
+		if gb.options.Debug {
+			fmt.Println("Servicing serial transfer interrupt")
+		}
+		gb.reg.PC = 0x58
 	case 4:
 		if gb.options.Debug {
 			fmt.Println("Servicing JOYPAD interrupt")
 		}
 		gb.reg.PC = 0x60
-	default:
-		panic(fmt.Sprintf("Servicing unknown interupt %d", i))
 	}
+
+	gb.finishCycles(interruptDuration)
 }
 
 // Executes the next instruction at the PC. Returns the length (in cycles) of the instruction
-func (gb *Gameboy) executeInstruction() (int, string) {
+func (gb *Gameboy) executeInstruction() int {
 	instructionCode := gb.mem.read8(gb.reg.PC)
-	instr, ok := (*gb.instructionMap)[instructionCode]
+	instr := gb.instructions[instructionCode]
 
-	if !ok {
-		panic(fmt.Sprintf("Unrecognized instruction %#02x at address %#04x", instructionCode, gb.reg.PC))
+	if gb.reg.haltBug {
+		// The PC is not incremented after the opcode fetch, so the opcode byte is read again as the
+		// first operand, or as the next opcode for single byte instructions.
+		gb.reg.haltBug = false
+		gb.reg.decPC(1)
 	}
 
-	if instr.name != "CB" {
+	if instructionCode != 0xCB {
 		if gb.options.Debug && gb.bootromSwapped {
 			if instr.bytes == 1 {
 				fmt.Printf("%#04x %-12s\n", gb.reg.PC, instr.name)
 			} else if instr.bytes == 2 {
-				fmt.Printf("%#04x %-12s%-#02x\n", gb.reg.PC, instr.name, uint16(readArgByte(gb.mem, gb.reg)))
+				fmt.Printf("%#04x %-12s%-#02x\n", gb.reg.PC, instr.name, uint16(gb.mem.peek8(gb.reg.PC+1)))
 			} else if instr.bytes == 3 {
-				fmt.Printf("%#04x %-12s%-#04x\n", gb.reg.PC, instr.name, uint16(readArgHalfword(gb.mem, gb.reg)))
+				fmt.Printf("%#04x %-12s%-#04x\n", gb.reg.PC, instr.name, gb.mem.peek16(gb.reg.PC+1))
 			}
 		}
 
-		cycles := instr.executor(gb.mem, gb.reg, instr)
+		taken := instr.executor(gb.mem, gb.reg, instr)
 
-		return cycles, instr.name
+		return instr.duration(taken)
 	} else {
 		cbCode := gb.mem.read8(gb.reg.PC + 1)
-		cb, ok := (*gb.cbInstruction)[cbCode]
-		if !ok {
-			panic(fmt.Sprintf("Unrecognized cb instruction %x at address %#04x", cbCode, gb.reg.PC))
-		}
+		cb := gb.cbInstructions[cbCode]
 		if gb.options.Debug && gb.bootromSwapped {
 			fmt.Printf("%#04x\t%s %s\n", gb.reg.PC, instr.name, cb.name)
 		}
 
-		cycles := cb.executor(gb.mem, gb.reg, cb)
-		return cycles + 4, cb.name
+		taken := cb.executor(gb.mem, gb.reg, cb)
+		return cb.duration(taken)
 	}
 }
 
+// The stack grows downwards: SP is decremented before writing and points at the last pushed byte
 func pushStack8(mem *memory, regs *register, val uint8) {
-	mem.write8(regs.SP, val)
 	regs.decSP(1)
+	mem.write8(regs.SP, val)
 }
 
 func pushStack16(mem *memory, reg *register, val uint16) {
-	pushStack8(mem, reg, leastSig16(val))
 	pushStack8(mem, reg, mostSig16(val))
-
+	pushStack8(mem, reg, leastSig16(val))
 }
 
 func popStack8(mem *memory, reg *register) uint8 {
+	val := mem.read8(reg.SP)
 	reg.incSP(1)
-	return mem.read8(reg.SP)
+	return val
 }
 
 func popStack16(mem *memory, reg *register) uint16 {
-	most := popStack8(mem, reg)
 	least := popStack8(mem, reg)
+	most := popStack8(mem, reg)
 	val := uint16(most)<<8 | uint16(least)
 	return val
 }
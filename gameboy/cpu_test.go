@@ -0,0 +1,300 @@
+package gameboy
+
+import (
+	"testing"
+	"time"
+)
+
+// Creates a Gameboy running the given code from work RAM, with the boot ROM already swapped out
+func stepGameboy(code []uint8) *Gameboy {
+	cartridge := make([]uint8, 32*1024)
+	gb, err := New(cartridge, &Options{})
+	if err != nil {
+		panic(err)
+	}
+	gb.bootromSwapped = true
+	gb.reg.PC = 0xc000
+	gb.reg.SP = 0xd000
+	gb.reg.interruptMaster = false
+	for i, b := range code {
+		gb.mem.write8(0xc000+uint16(i), b)
+	}
+	return gb
+}
+
+func TestEIDelay(t *testing.T) {
+	// EI, NOP, NOP with a pending VBLANK interrupt
+	gb := stepGameboy([]uint8{0xfb, 0x00, 0x00})
+	gb.mem.write8(0xFFFF, 0x1)
+	gb.mem.write8(0xFF0F, 0x1)
+
+	gb.Step()
+	if gb.reg.interruptMaster || gb.reg.PC != 0xc001 {
+		t.Errorf("Interrupts enabled directly after EI, PC %#04x", gb.reg.PC)
+	}
+
+	gb.Step()
+	if gb.reg.PC != 0x40 {
+		t.Errorf("Interrupt not serviced after the instruction following EI, PC %#04x", gb.reg.PC)
+	}
+}
+
+func TestDICancelsEI(t *testing.T) {
+	gb := stepGameboy([]uint8{0xfb, 0xf3, 0x00})
+
+	gb.Step()
+	gb.Step()
+	gb.Step()
+	if gb.reg.interruptMaster {
+		t.Errorf("DI directly after EI did not keep interrupts disabled")
+	}
+}
+
+func TestIllegalOpcodeLocksUp(t *testing.T) {
+	gb := stepGameboy([]uint8{0xd3, 0x00})
+	gb.reg.interruptMaster = true
+	gb.mem.write8(0xFFFF, 0x1)
+
+	gb.Step()
+	gb.mem.write8(0xFF0F, 0x1)
+	gb.Step()
+	if !gb.reg.locked || gb.reg.PC != 0xc000 {
+		t.Errorf("CPU did not lock up on an illegal opcode, PC %#04x", gb.reg.PC)
+	}
+}
+
+func TestHaltWakesWithInterruptsDisabled(t *testing.T) {
+	// HALT, INC A
+	gb := stepGameboy([]uint8{0x76, 0x3c})
+	gb.mem.write8(0xFFFF, 0x4)
+
+	gb.Step()
+	gb.Step()
+	if !gb.reg.halted {
+		t.Fatalf("CPU did not halt")
+	}
+
+	gb.mem.write8(0xFF0F, 0x4)
+	gb.Step()
+	gb.Step()
+	if gb.reg.halted || gb.reg.A != 1 || gb.reg.PC != 0xc002 {
+		t.Errorf("CPU did not continue after HALT without servicing, A %d PC %#04x", gb.reg.A, gb.reg.PC)
+	}
+}
+
+func TestHaltBug(t *testing.T) {
+	// HALT, LD A,d8 0x14, which executes as LD A,0x3e followed by INC D
+	gb := stepGameboy([]uint8{0x76, 0x3e, 0x14})
+	gb.mem.write8(0xFFFF, 0x1)
+	gb.mem.write8(0xFF0F, 0x1)
+
+	gb.Step()
+	if gb.reg.halted {
+		t.Fatalf("CPU halted with a pending interrupt and interrupts disabled")
+	}
+
+	gb.Step()
+	gb.Step()
+	if gb.reg.A != 0x3e || gb.reg.D != 1 || gb.reg.PC != 0xc003 {
+		t.Errorf("HALT bug not emulated, A %#02x D %d PC %#04x", gb.reg.A, gb.reg.D, gb.reg.PC)
+	}
+}
+
+func TestEIHaltWithPendingInterrupt(t *testing.T) {
+	// EI, HALT with a pending VBLANK interrupt, the handler is INC A, RETI
+	gb := stepGameboy([]uint8{0xfb, 0x76})
+	gb.mem.switchableRomBank[0][0x40] = 0x3c
+	gb.mem.switchableRomBank[0][0x41] = 0xd9
+	gb.mem.write8(0xFFFF, 0x1)
+	gb.mem.write8(0xFF0F, 0x1)
+
+	gb.Step()
+	gb.Step()
+	if gb.reg.PC != 0x40 || gb.reg.haltBug {
+		t.Fatalf("Expected the interrupt to be serviced without the HALT bug, PC %#04x", gb.reg.PC)
+	}
+
+	gb.Step()
+	gb.Step()
+	if gb.reg.A != 1 || gb.reg.PC != 0xc001 {
+		t.Errorf("Expected the handler to run once and return to the HALT, A %d PC %#04x", gb.reg.A, gb.reg.PC)
+	}
+}
+
+func TestInterruptRequestsKeptWhileDisabled(t *testing.T) {
+	gb := stepGameboy([]uint8{0x00, 0x00})
+	gb.mem.write8(0xFFFF, 0x1F)
+	gb.mem.write8(0xFF0F, 0x5)
+
+	gb.Step()
+	if gb.mem.read8(0xFF0F)&0x1F != 0x5 {
+		t.Errorf("Interrupt requests cleared while interrupts are disabled: %#02x", gb.mem.read8(0xFF0F))
+	}
+}
+
+func TestInterruptPriority(t *testing.T) {
+	gb := stepGameboy([]uint8{0x00, 0x00})
+	gb.reg.interruptMaster = true
+	gb.mem.write8(0xFFFF, 0x1F)
+	gb.mem.write8(0xFF0F, 0x0C)
+
+	gb.Step()
+	if gb.reg.PC != 0x50 || gb.reg.interruptMaster {
+		t.Errorf("Timer interrupt not serviced first, PC %#04x", gb.reg.PC)
+	}
+	if gb.mem.read8(0xFF0F)&0x1F != 0x08 {
+		t.Errorf("Serial request not kept: %#02x", gb.mem.read8(0xFF0F))
+	}
+	if popStack16(gb.mem, gb.reg) != 0xc001 {
+		t.Errorf("Return address not pushed")
+	}
+}
+
+func TestSerialInterrupt(t *testing.T) {
+	gb := stepGameboy([]uint8{0x00, 0x00})
+	gb.reg.interruptMaster = true
+	gb.mem.write8(0xFFFF, 0x08)
+	gb.mem.write8(0xFF0F, 0x08)
+
+	gb.Step()
+	if gb.reg.PC != 0x58 {
+		t.Errorf("Serial interrupt not serviced, PC %#04x", gb.reg.PC)
+	}
+}
+
+func TestCycleAccurateMemoryAccess(t *testing.T) {
+	for _, accurate := range []bool{false, true} {
+		// LDH A,(TIMA) reads TIMA in its third M-cycle
+		gb := stepGameboy([]uint8{0xf0, 0x05})
+		if accurate {
+			gb.mem.clock = &clock{tick: gb.tick}
+		}
+		gb.mem.timer = timer{counter: 4, tac: 0x5}
+
+		gb.Step()
+
+		expected := uint8(0)
+		if accurate {
+			// The falling edge of counter bit 3 happens before the read
+			expected = 1
+		}
+		if gb.reg.A != expected {
+			t.Errorf("Expected TIMA to read %d with cycle accuracy %v, got %d", expected, accurate, gb.reg.A)
+		}
+		if gb.mem.timer.counter != 16 {
+			t.Errorf("Expected the timer to advance 12 cycles with cycle accuracy %v, got %d", accurate, gb.mem.timer.counter-4)
+		}
+	}
+}
+
+func TestDMACopiesOneBytePerCycle(t *testing.T) {
+	gb := stepGameboy([]uint8{})
+	gb.mem.write8(0xc100, 0x12)
+	gb.mem.write8(0xc101, 0x34)
+	gb.mem.write8(0xc19f, 0x56)
+	gb.mem.write8(0xFF46, 0xc1)
+
+	gb.tick(8)
+	if gb.mem.spriteAttribMemory[0] != 0x12 || gb.mem.spriteAttribMemory[1] != 0x34 || gb.mem.spriteAttribMemory[2] != 0 {
+		t.Errorf("Expected two bytes to be copied after two M-cycles, got % x", gb.mem.spriteAttribMemory[:3])
+	}
+
+	gb.tick(158 * 4)
+	if gb.mem.dma.active || gb.mem.spriteAttribMemory[0x9f] != 0x56 {
+		t.Errorf("Expected the DMA to be finished after 160 M-cycles")
+	}
+}
+
+func TestFaultIsRecoverable(t *testing.T) {
+	// LD (0x2000),A selects a ROM bank, which is not implemented for HuC3
+	gb := stepGameboy([]uint8{0xea, 0x00, 0x20, 0x00})
+	gb.mem.mbc = newMBC(&cartridgeInfo{CartType: hudson_huc3}, gb.mem)
+
+	err := gb.Step()
+	fault, ok := err.(*Fault)
+	if !ok {
+		t.Fatalf("Expected a *Fault, got %v", err)
+	}
+	if fault.PC != 0xc000 || fault.Address != 0x2000 {
+		t.Errorf("Expected the fault to point at the write to 0x2000 at 0xc000, got %v", fault)
+	}
+
+	if err := gb.Step(); err != nil || gb.reg.PC != 0xc004 {
+		t.Errorf("Expected the emulation to continue after a fault, got %v at %#04x", err, gb.reg.PC)
+	}
+}
+
+func TestJoypadInterrupt(t *testing.T) {
+	gb := stepGameboy([]uint8{})
+	// Select the directions
+	gb.mem.write8(0xFF00, 0x20)
+
+	gb.SetButtons(ButtonStart)
+	if testBit(gb.mem.ioPorts[0x0F], 4) {
+		t.Errorf("Expected no interrupt for a button on a line that is not selected")
+	}
+
+	gb.SetButtons(ButtonStart | ButtonDown)
+	if !testBit(gb.mem.ioPorts[0x0F], 4) {
+		t.Errorf("Expected an interrupt when a selected line goes low")
+	}
+
+	gb.mem.ioPorts[0x0F] = 0
+	gb.SetButtons(ButtonStart)
+	if testBit(gb.mem.ioPorts[0x0F], 4) {
+		t.Errorf("Expected no interrupt when a button is released")
+	}
+
+	// Selecting the action buttons while Start is held also pulls a line low
+	gb.mem.write8(0xFF00, 0x10)
+	if !testBit(gb.mem.ioPorts[0x0F], 4) {
+		t.Errorf("Expected an interrupt when a line with a pressed button is selected")
+	}
+}
+
+func TestStopEndsOnButtonPress(t *testing.T) {
+	// STOP, NOP
+	gb := stepGameboy([]uint8{0x10, 0x00, 0x00})
+	gb.mem.write8(0xFF00, 0x10)
+	gb.mem.write8(0xFFFF, 0x10)
+	gb.reg.interruptMaster = true
+
+	gb.Step()
+	gb.Step()
+	if !gb.reg.stopped {
+		t.Fatalf("Expected the CPU to be stopped until a button is pressed")
+	}
+
+	gb.SetButtons(ButtonA)
+	gb.Step()
+	if gb.reg.stopped {
+		t.Fatalf("Expected a button press to end the STOP")
+	}
+
+	gb.Step()
+	if gb.reg.PC != 0x60 {
+		t.Errorf("Expected the joypad interrupt to be serviced, PC is %#04x", gb.reg.PC)
+	}
+}
+
+func TestRunFrameReturnsWhileStopped(t *testing.T) {
+	// STOP, NOP
+	gb := stepGameboy([]uint8{0x10, 0x00, 0x00})
+	gb.mem.write8(0xFF00, 0x10)
+	gb.Step()
+
+	done := make(chan struct{})
+	go func() {
+		gb.RunFrame()
+		gb.RunFrame()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Expected RunFrame to return while the CPU is stopped")
+	}
+	if !gb.reg.stopped {
+		t.Errorf("Expected the CPU to stay stopped without a button press")
+	}
+}
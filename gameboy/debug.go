@@ -9,24 +9,24 @@ import (
 )
 
 type Command struct {
-	StepCommand       bool        `@("s"|"")`
-	QuitCommand       bool        `| @"q"`
-	BreakpointCommand *Breakpoint `| "b" @@`
-	PrintRegsCommand  bool        `| @"p"`
-	StatCommand       *Stat       `| "t" @@`
-	RunCommand        bool        `| @"r"`
-	HelpCommand       bool        `| @"h"`
+	StepCommand       bool        `parser:"@(\"s\"|\"\")"`
+	QuitCommand       bool        `parser:"| @\"q\""`
+	BreakpointCommand *Breakpoint `parser:"| \"b\" @@"`
+	PrintRegsCommand  bool        `parser:"| @\"p\""`
+	StatCommand       *Stat       `parser:"| \"t\" @@"`
+	RunCommand        bool        `parser:"| @\"r\""`
+	HelpCommand       bool        `parser:"| @\"h\""`
 }
 
 type Stat struct {
-	InterruptStatCommand bool `@"i"`
-	MemoryStatCommand    bool `| @"m"`
-	StackStatCommand     bool `| @"s"`
+	InterruptStatCommand bool `parser:"@\"i\""`
+	MemoryStatCommand    bool `parser:"| @\"m\""`
+	StackStatCommand     bool `parser:"| @\"s\""`
 }
 
 type Breakpoint struct {
-	SetBreakpoint   *int `@Int`
-	ListBreakpoints bool `| @"l"`
+	SetBreakpoint   *int `parser:"@Int"`
+	ListBreakpoints bool `parser:"| @\"l\""`
 }
 
 type Debugger struct {
@@ -40,7 +40,7 @@ func RunDebugger(gb *Gameboy, updateInputFunction func(input *Input)) {
 	reader := bufio.NewReader(os.Stdin)
 	stopped := false
 
-	parser, err := participle.Build(&Command{}, nil)
+	parser, err := participle.Build(&Command{})
 	if err != nil {
 		panic(err)
 	}
@@ -72,9 +72,11 @@ func RunDebugger(gb *Gameboy, updateInputFunction func(input *Input)) {
 
 func (debugger *Debugger) handleCommand(command Command, updateInputFunction func(input *Input)) bool {
 	if command.StepCommand {
-		debugger.gb.Step()
+		if err := debugger.gb.Step(); err != nil {
+			fmt.Println(err)
+		}
 		updateInputFunction(debugger.input)
-		debugger.gb.HandleInput(debugger.input)
+		debugger.gb.SetInput(*debugger.input)
 	} else if command.QuitCommand {
 		return true
 	} else if command.BreakpointCommand != nil {
@@ -92,9 +94,12 @@ func (debugger *Debugger) handleCommand(command Command, updateInputFunction fun
 	} else if command.RunCommand {
 		hit, bp := false, uint16(0)
 		for !hit {
-			debugger.gb.Step()
+			if err := debugger.gb.Step(); err != nil {
+				fmt.Println(err)
+				return false
+			}
 			updateInputFunction(debugger.input)
-			debugger.gb.HandleInput(debugger.input)
+			debugger.gb.SetInput(*debugger.input)
 			hit, bp = debugger.breakpointHit(debugger.gb.PC())
 		}
 		fmt.Printf("Hit breakpoint at %#04x\n", bp)
@@ -119,7 +124,7 @@ func (debugger *Debugger) handleCommand(command Command, updateInputFunction fun
 		if command.StatCommand.InterruptStatCommand {
 			ifReg := debugger.gb.mem.ioPorts[0x0F]
 			ieReg := debugger.gb.mem.interruptEnableRegister
-			fmt.Printf("Interrupt master: %t\n", debugger.gb.interruptMaster)
+			fmt.Printf("Interrupt master: %t\n", debugger.gb.reg.interruptMaster)
 			fmt.Println("[Name] [Enabled] [Requested]")
 			fmt.Printf("VBLANK: %t %t\n", testBit(ieReg, 0), testBit(ifReg, 0))
 			fmt.Printf("LCDC: %t %t\n", testBit(ieReg, 1), testBit(ifReg, 1))
@@ -128,23 +133,13 @@ func (debugger *Debugger) handleCommand(command Command, updateInputFunction fun
 		} else if command.StatCommand.MemoryStatCommand {
 			mem := debugger.gb.mem
 
-			fmt.Printf("Memor settings:\n")
-			fmt.Printf("MBC1: %t\n", mem.memorySettings.mbc1)
-			fmt.Printf("MBC2: %t\n", mem.memorySettings.mbc2)
-			fmt.Printf("MBC3: %t\n", mem.memorySettings.mbc3)
-			if mem.memorySettings.bankingMode == romBankingMode {
-				fmt.Printf("Banking mode: ROM\n")
-			} else if mem.memorySettings.bankingMode == ramBankingMode {
-				fmt.Printf("Banking mode: RAM\n")
-			}
-			fmt.Printf("Current ROM bank: %d\n", mem.memorySettings.currentROMBank)
-			fmt.Printf("Current RAM bank: %d\n", mem.memorySettings.currentRAMBank)
+			fmt.Printf("Memory bank controller: %s\n", mem.mbc)
 		} else if command.StatCommand.StackStatCommand {
 			mem := debugger.gb.mem
 			reg := debugger.gb.reg
 			fmt.Println("Stack dump:")
 			for i := 0; i < 10; i++ {
-				fmt.Printf("%#04x %#02x\n",reg.SP + uint16(i), mem.read8(reg.SP + uint16(i)))
+				fmt.Printf("%#04x %#02x\n",reg.SP + uint16(i), mem.peek8(reg.SP + uint16(i)))
 			}
 
 		}
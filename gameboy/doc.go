@@ -0,0 +1,26 @@
+/*
+Package gameboy emulates the original Gameboy.
+
+A Gameboy is created from the contents of a ROM and can be run one frame at a time:
+
+	gb, err := gameboy.New(rom, nil)
+	if err != nil {
+		return err
+	}
+	for {
+		gb.SetInput(gameboy.Input{A: true})
+		frame, _, err := gb.RunFrame()
+		if err != nil {
+			return err
+		}
+		draw(frame)
+	}
+
+RunCycles runs a number of cycles instead of a whole frame. The finished frames can also be
+received with a FrameSink. Read and Write give access to the
+memory as the CPU sees it, for example to inspect or patch the state of a game.
+
+SaveState and LoadState save and restore the complete machine state. RecordMovie records the
+buttons of every frame into a Movie, which PlayMovie plays back to reproduce a run exactly.
+*/
+package gameboy
@@ -0,0 +1,37 @@
+package gameboy
+
+import "fmt"
+
+// Returned when the ROM is too small to contain a cartridge header and two ROM banks
+type RomSizeError struct {
+	Size int
+}
+
+func (e *RomSizeError) Error() string {
+	return fmt.Sprintf("ROM of %d bytes is not a valid cartridge", e.Size)
+}
+
+// Returned when a cartridge header field has a value the emulator does not support
+type HeaderError struct {
+	Field   string
+	Address uint16
+	Value   uint8
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("unsupported %s %#02x at %#04x in the cartridge header", e.Field, e.Value, e.Address)
+}
+
+/*
+Returned by Step when the game uses hardware that is not emulated, for example an unsupported
+memory bank controller command. The access is ignored, so the emulation can continue after a fault.
+*/
+type Fault struct {
+	PC      uint16
+	Address uint16
+	Reason  string
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("%#04x: %s at %#04x", f.PC, f.Reason, f.Address)
+}
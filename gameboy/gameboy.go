@@ -2,73 +2,215 @@ package gameboy
 
 import (
 	"fmt"
-	"github.com/banthar/Go-SDL/sdl"
 )
 
 type Options struct {
-	Scaling int
-	Debug   bool
-	Speed   int
+	Debug bool
+	// Advance the timer, PPU and DMA on every memory access instead of after every instruction
+	CycleAccurate bool
+	// Run the real-time clock of MBC3 cartridges on the time of the host instead of the emulated time
+	HostClock bool
 }
 
+// The buttons that are pressed
 type Input struct {
-	A     bool
-	B     bool
-	LEFT  bool
-	RIGHT bool
-	UP    bool
-	DOWN  bool
-	ENTER bool
-	SPACE bool
+	A      bool
+	B      bool
+	Left   bool
+	Right  bool
+	Up     bool
+	Down   bool
+	Start  bool
+	Select bool
 }
 
-type timer struct {
-	t int
-	m int
-	d int
+/*
+The buttons as a bitmask. The lower nibble holds the action buttons and the upper nibble the
+directions, in the order of the joypad register.
+*/
+const (
+	ButtonA uint8 = 1 << iota
+	ButtonB
+	ButtonSelect
+	ButtonStart
+	ButtonRight
+	ButtonLeft
+	ButtonUp
+	ButtonDown
+)
+
+// Returns the pressed buttons as a bitmask of ButtonA, ButtonB and so on
+func (input Input) Buttons() uint8 {
+	buttons := uint8(0)
+	pressed := []bool{input.A, input.B, input.Select, input.Start, input.Right, input.Left, input.Up, input.Down}
+	for i, p := range pressed {
+		if p {
+			buttons |= 1 << uint(i)
+		}
+	}
+	return buttons
 }
 
 type Gameboy struct {
 	cartridgeInfo  *cartridgeInfo
-	instructionMap *map[uint8]*instruction
-	cbInstruction  *map[uint8]*cbInstruction
+	instructions   [256]*instruction
+	cbInstructions [256]*cbInstruction
 	mem            *memory
 	graphics       *graphics
 	reg            *register
 	options        *Options
 	cartridge      []uint8
 
-	timer *timer
+	// The number of cycles executed since the start
+	cycles uint64
+
+	bootromSwapped bool
+
+	// The movie that is recorded or played back by RunFrame, nil when there is none
+	movie      *Movie
+	recording  bool
+	movieFrame int
+}
+
+const (
+	// The CPU runs at 4.194304 MHz and a frame takes 154 lines of 456 cycles, about 59.73 frames per second
+	ClockSpeed     = 4194304
+	CyclesPerFrame = 70224
+	FrameRate      = float64(ClockSpeed) / CyclesPerFrame
+)
 
-	interruptMaster           bool
-	interruptEnableScheduled  bool
-	interruptDisableScheduled bool
-	halted                    bool
-	stopped                   bool
-	bootromSwapped            bool
+// The smallest cartridge has two 16 kB ROM banks
+const minimumRomSize = 0x8000
+
+/*
+Creates a Gameboy that runs the ROM without any video output. The frames can be read with Frame or
+passed to a FrameSink using SetFrameSink. A nil options uses the defaults.
+*/
+func New(rom []uint8, options *Options) (*Gameboy, error) {
+	return Initialize(rom, nil, options)
 }
 
-func Initialize(cart []uint8, renderer *sdl.Surface, options *Options) Gameboy {
-	cartInfo := createCartridgeInfo(cart)
-	instructionMap := createInstructionMap()
-	cbInstrucionMap := createCBInstructionMap()
+/*
+Creates a Gameboy that hands every finished frame to the sink. A nil options uses the defaults.
+Returns a *RomSizeError or a *HeaderError when the ROM can not be emulated.
+*/
+func Initialize(cart []uint8, sink FrameSink, options *Options) (*Gameboy, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	if len(cart) < minimumRomSize {
+		return nil, &RomSizeError{Size: len(cart)}
+	}
+	cartInfo, err := createCartridgeInfo(cart)
+	if err != nil {
+		return nil, err
+	}
 	mem := memInit(cart, cartInfo)
-	graphics := createGraphics(mem.videoRam[:], mem.ioPorts[:], mem.spriteAttribMemory[:], renderer, options.Speed, options.Scaling)
+	graphics := createGraphics(mem.videoRam[:], mem.ioPorts[:], mem.spriteAttribMemory[:], sink)
 	registers := new(register)
+	registers.interruptMaster = true
+
+	gameboy := &Gameboy{
+		cartridgeInfo:  cartInfo,
+		instructions:   createInstructionTable(),
+		cbInstructions: createCBInstructionTable(),
+		mem:            mem,
+		graphics:       graphics,
+		reg:            registers,
+		options:        options,
+		cartridge:      cart,
+	}
+
+	if options.CycleAccurate {
+		mem.clock = &clock{tick: gameboy.tick}
+	}
+	if options.HostClock {
+		mem.useHostClock()
+	}
+
+	if options.Debug {
+		fmt.Printf("GoBoy initialized:\n%s", cartridgeInfoString(*cartInfo))
+	}
+	return gameboy, nil
+}
 
-	gameboy := Gameboy{
-		cartridgeInfo:   cartInfo,
-		instructionMap:  instructionMap,
-		cbInstruction:   cbInstrucionMap,
-		mem:             mem,
-		graphics:        graphics,
-		reg:             registers,
-		options:         options,
-		cartridge:       cart,
-		timer:           new(timer),
-		interruptMaster: true,
+// Hands every finished frame to the sink, a nil sink disables the video output
+func (gb *Gameboy) SetFrameSink(sink FrameSink) {
+	gb.graphics.sink = sink
+}
+
+/*
+Runs the emulation until the PPU finished the next frame, which is when it enters VBLANK. Returns the
+finished frame and the number of cycles executed. Stops early when Step returns a fault.
+*/
+func (gb *Gameboy) RunFrame() (*Frame, int, error) {
+	gb.updateMovie()
+	start := gb.cycles
+	frames := gb.graphics.frames
+	for gb.graphics.frames == frames {
+		if err := gb.Step(); err != nil {
+			return nil, int(gb.cycles - start), err
+		}
+	}
+	return &gb.graphics.frame, int(gb.cycles - start), nil
+}
+
+/*
+Runs the emulation for at least the given number of cycles. Instructions are not interrupted, so a
+few more cycles can be executed. Returns the number of cycles executed. Stops early on a fault.
+*/
+func (gb *Gameboy) RunCycles(cycles int) (int, error) {
+	start := gb.cycles
+	for gb.cycles-start < uint64(cycles) {
+		if err := gb.Step(); err != nil {
+			return int(gb.cycles - start), err
+		}
+	}
+	return int(gb.cycles - start), nil
+}
+
+/*
+Calls the function when the game switches the rumble motor of the cartridge on or off. Only MBC5
+cartridges with a rumble motor use it.
+*/
+func (gb *Gameboy) SetRumble(rumble func(on bool)) {
+	gb.mem.motor = nil
+	if rumble != nil {
+		gb.mem.motor = &motor{rumble: rumble}
+	}
+}
+
+// Returns the last finished frame. It is overwritten when the next frame is finished.
+func (gb *Gameboy) Frame() *Frame {
+	return &gb.graphics.frame
+}
+
+/*
+Sets the buttons that are pressed, the game sees them from the next read of the joypad register. The
+buttons are ignored while a movie is played back, the movie sets them.
+*/
+func (gb *Gameboy) SetInput(input Input) {
+	gb.SetButtons(input.Buttons())
+}
+
+// Sets the buttons that are pressed as a bitmask of ButtonA, ButtonB and so on, like SetInput
+func (gb *Gameboy) SetButtons(buttons uint8) {
+	if gb.PlayingMovie() {
+		return
 	}
+	gb.setButtons(buttons)
+}
+
+func (gb *Gameboy) setButtons(buttons uint8) {
+	gb.mem.updateJoypad(gb.mem.ioPorts[0x00], buttons)
+}
+
+// Reads a byte from the address space as seen by the CPU, without advancing the emulation
+func (gb *Gameboy) Read(address uint16) uint8 {
+	return gb.mem.peek8(address)
+}
 
-	fmt.Printf("GoBoy initialized:\n%s", cartridgeInfoString(*cartInfo))
-	return gameboy
+// Writes a byte to the address space as the CPU would, without advancing the emulation
+func (gb *Gameboy) Write(address uint16, val uint8) {
+	gb.mem.poke8(address, val)
 }
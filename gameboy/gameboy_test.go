@@ -0,0 +1,119 @@
+package gameboy
+
+import "testing"
+
+func TestNewRejectsShortRom(t *testing.T) {
+	if _, err := New(make([]uint8, 0x100), nil); err == nil {
+		t.Errorf("Expected an error for a ROM without a complete header")
+	}
+}
+
+func TestNilOptions(t *testing.T) {
+	if _, err := Initialize(make([]uint8, 32*1024), nil, nil); err != nil {
+		t.Errorf("Expected Initialize to use the defaults for nil options, got %v", err)
+	}
+}
+
+func TestRunFrame(t *testing.T) {
+	gb, err := New(make([]uint8, 32*1024), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := 0
+	gb.SetFrameSink(FrameSinkFunc(func(frame *Frame) {
+		frames++
+	}))
+
+	if _, _, err := gb.RunFrame(); err != nil {
+		t.Fatal(err)
+	}
+	frame, cycles, err := gb.RunFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frames != 2 {
+		t.Errorf("Expected 2 frames, got %d", frames)
+	}
+	if frame != gb.Frame() {
+		t.Errorf("Expected RunFrame to return the finished frame")
+	}
+	// A frame takes 154 lines of 456 cycles
+	if cycles < 154*456 || cycles > 154*456+154*3*24 {
+		t.Errorf("Expected a frame to take about %d cycles, got %d", 154*456, cycles)
+	}
+}
+
+func TestRunCycles(t *testing.T) {
+	gb, err := New(make([]uint8, 32*1024), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cycles, err := gb.RunCycles(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The longest instruction takes 24 cycles
+	if cycles < 1000 || cycles >= 1024 {
+		t.Errorf("Expected 1000 cycles to be run, got %d", cycles)
+	}
+	if gb.cycles != uint64(cycles) {
+		t.Errorf("Expected %d cycles to be counted, got %d", cycles, gb.cycles)
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	gb, err := New(make([]uint8, 32*1024), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gb.Write(0xc123, 0x42)
+	gb.Write(0xfe00, 0x10)
+
+	if val := gb.Read(0xe123); val != 0x42 {
+		t.Errorf("Expected to read the written byte from the echo RAM, got %#02x", val)
+	}
+	if val := gb.Read(0xfe00); val != 0x10 {
+		t.Errorf("Expected to read the written byte from the sprite attribute memory, got %#02x", val)
+	}
+}
+
+func TestInputButtons(t *testing.T) {
+	input := Input{A: true, Start: true, Down: true}
+	if buttons := input.Buttons(); buttons != ButtonA|ButtonStart|ButtonDown {
+		t.Errorf("Expected the bitmask %08b, got %08b", ButtonA|ButtonStart|ButtonDown, buttons)
+	}
+}
+
+func TestJoypadRegister(t *testing.T) {
+	gb, err := New(make([]uint8, 32*1024), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if joypad := gb.Read(0xFF00); joypad != 0xCF {
+		t.Errorf("Expected no buttons to be pressed after power on, got %08b", joypad)
+	}
+
+	gb.SetButtons(ButtonStart | ButtonDown)
+
+	// Select the directions
+	gb.Write(0xFF00, 0x20)
+	if joypad := gb.Read(0xFF00); joypad != 0xE7 {
+		t.Errorf("Expected only Down to read as pressed, got %08b", joypad)
+	}
+
+	// Select the action buttons, writes to the button bits are ignored
+	gb.Write(0xFF00, 0x1F)
+	if joypad := gb.Read(0xFF00); joypad != 0xD7 {
+		t.Errorf("Expected only Start to read as pressed, got %08b", joypad)
+	}
+
+	gb.SetInput(Input{})
+	if joypad := gb.Read(0xFF00); joypad != 0xDF {
+		t.Errorf("Expected the released buttons to be seen directly, got %08b", joypad)
+	}
+}
@@ -1,9 +1,5 @@
 package gameboy
 
-import (
-	"github.com/banthar/Go-SDL/sdl"
-)
-
 const (
 	ADDRESS_IO_PORTS = 0xFF00
 
@@ -18,37 +14,59 @@ const (
 	IF       uint16 = 0xFF0F - ADDRESS_IO_PORTS
 )
 
+const (
+	ScreenWidth  = 160
+	ScreenHeight = 144
+)
+
+// A finished frame, indexed by line and then by pixel
+type Frame [ScreenHeight][ScreenWidth]Color
+
+type Color struct {
+	R uint8
+	G uint8
+	B uint8
+}
+
+/*
+Receives every finished frame when the PPU enters VBLANK. The frame is reused for the next frame, so
+it should be copied if it is needed after ShowFrame returns.
+*/
+type FrameSink interface {
+	ShowFrame(frame *Frame)
+}
+
+// Adapts a function to a FrameSink
+type FrameSinkFunc func(frame *Frame)
+
+func (f FrameSinkFunc) ShowFrame(frame *Frame) {
+	f(frame)
+}
+
 type graphics struct {
 	videoRam              []uint8
 	ioPorts               []uint8
 	spriteAttributeMemory []uint8
 
-	renderer *sdl.Surface
+	// Receives the finished frames, nil when running without output
+	sink FrameSink
 
-	scaling int
-	speed   int
-
-	screen [144][160]drawColor
+	// The frame that is being drawn and the last finished frame
+	screen Frame
+	frame  Frame
+	frames int
 
 	mode      int
 	modeclock int
 	line      uint8
 }
 
-type drawColor struct {
-	r uint8
-	g uint8
-	b uint8
-}
-
-func createGraphics(videoRam []uint8, ioPorts []uint8, spriteAttributeMemory []uint8, rend *sdl.Surface, speed int, scale int) *graphics {
+func createGraphics(videoRam []uint8, ioPorts []uint8, spriteAttributeMemory []uint8, sink FrameSink) *graphics {
 	return &graphics{
 		videoRam:              videoRam,
 		ioPorts:               ioPorts,
 		spriteAttributeMemory: spriteAttributeMemory,
-		renderer:              rend,
-		scaling:               scale,
-		speed:                 speed,
+		sink:                  sink,
 	}
 }
 
@@ -265,33 +283,31 @@ func (graphics *graphics) drawBackground(background *[160]uint8) {
 }
 
 func (graphics *graphics) showData() {
-	for j := 0; j < len(graphics.screen); j++ {
-		for i := 0; i < len(graphics.screen[0]); i++ {
-			rect := sdl.Rect{X: int16(i * graphics.scaling), Y: int16(j * graphics.scaling), W: uint16(graphics.scaling), H: uint16(graphics.scaling)}
-			graphics.renderer.FillRect(&rect, sdl.MapRGBA(graphics.renderer.Format, graphics.screen[j][i].r, graphics.screen[j][i].g, graphics.screen[j][i].b, 0xff))
-		}
+	graphics.frame = graphics.screen
+	graphics.frames++
+	if graphics.sink != nil {
+		graphics.sink.ShowFrame(&graphics.frame)
 	}
-	graphics.renderer.Flip()
 }
 
 func (graphics *graphics) isLCDEnabled() bool {
 	return testBit(graphics.ioPorts[0x40], 7)
 }
 
-func (graphics *graphics) getColor(colorByte uint8, paletteAddress uint16) drawColor {
+func (graphics *graphics) getColor(colorByte uint8, paletteAddress uint16) Color {
 	palette := graphics.ioPorts[paletteAddress]
 
 	colorNo := (palette >> (colorByte * 2)) & 0x3
 
 	switch colorNo {
 	case 0:
-		return drawColor{255, 255, 255}
+		return Color{255, 255, 255}
 	case 1:
-		return drawColor{0xcc, 0xcc, 0xcc}
+		return Color{0xcc, 0xcc, 0xcc}
 	case 2:
-		return drawColor{0x77, 0x77, 0x77}
+		return Color{0x77, 0x77, 0x77}
 	case 3:
-		return drawColor{0, 0, 0}
+		return Color{0, 0, 0}
 	default:
 		panic("Unknown color!")
 	}
@@ -0,0 +1,45 @@
+package gameboy
+
+import "testing"
+
+func TestFrameSinkReceivesFrames(t *testing.T) {
+	frames := 0
+	var received *Frame
+	sink := FrameSinkFunc(func(frame *Frame) {
+		frames++
+		received = frame
+	})
+
+	gb, err := Initialize(make([]uint8, 32*1024), sink, &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gb.graphics.screen[0][0] = Color{1, 2, 3}
+
+	// A frame takes 154 lines of 456 cycles
+	for i := 0; i < 154*456/4; i++ {
+		gb.tick(4)
+	}
+
+	if frames != 1 {
+		t.Fatalf("Expected 1 frame, got %d", frames)
+	}
+	if received[0][0] != (Color{1, 2, 3}) {
+		t.Errorf("Expected the sink to receive the screen buffer, got %+v", received[0][0])
+	}
+}
+
+func TestHeadlessRunsWithoutSink(t *testing.T) {
+	gb, err := New(make([]uint8, 32*1024), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 154*456/4; i++ {
+		gb.tick(4)
+	}
+
+	if !testBit(gb.mem.ioPorts[0x0F], 0) {
+		t.Errorf("Expected the PPU to request VBLANK without a sink")
+	}
+}
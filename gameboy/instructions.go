@@ -1,7 +1,5 @@
 package gameboy
 
-import "fmt"
-
 /*
 CPU Instruction structure. Has two durations for some instructions: action and noop.
 When action noop is 0, the instruction always takes the action duration.
@@ -21,10 +19,24 @@ type instruction struct {
 	executor instructionExecutor
 }
 
-type instructionExecutor func(mem *memory, reg *register, instr *instruction) int
+/*
+Executes the instruction and returns whether its action was taken. Only conditional jumps, calls
+and returns can skip their action; all other instructions return true. The cycles an instruction
+takes are derived from its durations, see instruction.duration.
+*/
+type instructionExecutor func(mem *memory, reg *register, instr *instruction) bool
+
+// Returns the number of cycles the instruction takes when its action was or was not taken
+func (instr *instruction) duration(taken bool) int {
+	if taken || instr.durationNoop == 0 {
+		return instr.durationAction
+	}
+	return instr.durationNoop
+}
 
-func createInstructionMap() *map[uint8]*instruction {
-	return &map[uint8]*instruction{
+// Creates the decode table of the base instructions, indexed by opcode
+func createInstructionTable() [256]*instruction {
+	return [256]*instruction{
 		0x00: newInstruction("NOOP", 1, 4, noop),
 		0x01: newInstruction("LD BC,d16", 3, 12, ldBCnn),
 		0x02: newInstruction("LD (BC),A", 1, 8, ldBCA),
@@ -57,7 +69,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0x1c: newInstruction("INC E", 1, 4, incE),
 		0x1d: newInstruction("DEC E", 1, 4, decE),
 		0x1e: newInstruction("LD E,d8", 2, 8, ldEd8),
-		0x1f: newInstruction("RRA", 1, 4, rrA),
+		0x1f: newInstruction("RRA", 1, 4, rra),
 
 		0x20: newConditionalInstruction("JR NZ,r8", 2, 12, 8, jrNZr8),
 		0x21: newInstruction("LD HL,d16", 3, 12, ldHLd16),
@@ -71,7 +83,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0x29: newInstruction("ADD HL,HL", 1, 8, addHLHL),
 		0x2a: newInstruction("LD A,(HL+)", 1, 8, ldAHLP),
 		0x2b: newInstruction("DEC HL", 1, 8, decHL),
-		0x2c: newInstruction("INC L", 1, 8, incL),
+		0x2c: newInstruction("INC L", 1, 4, incL),
 		0x2d: newInstruction("DEC L", 1, 4, decL),
 		0x2e: newInstruction("LD L,d8", 2, 8, ldLD8),
 		0x2f: newInstruction("CPL", 1, 4, cpl),
@@ -79,6 +91,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0x30: newConditionalInstruction("JR NC,r8", 2, 12, 8, jrNCr8),
 		0x31: newInstruction("LD SP,d16", 3, 12, ldSPd16),
 		0x32: newInstruction("LDD (HL-),A", 1, 8, lddHLA),
+		0x33: newInstruction("INC SP", 1, 8, incSP),
 		0x34: newInstruction("INC (HL)", 1, 12, incAHL),
 		0x35: newInstruction("DEC (HL)", 1, 12, decHl),
 		0x36: newInstruction("LD (HL),d8", 2, 12, ldHLd8),
@@ -167,7 +180,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0x84: newInstruction("ADD A,H", 1, 4, addAH),
 		0x85: newInstruction("ADD A,L", 1, 4, addAL),
 		0x86: newInstruction("ADD A,(HL)", 1, 8, addAHL),
-		0x87: newInstruction("ADD A,A", 1, 8, addAA),
+		0x87: newInstruction("ADD A,A", 1, 4, addAA),
 		0x88: newInstruction("ADC A,B", 1, 4, adcAB),
 		0x89: newInstruction("ADC A,C", 1, 4, adcAC),
 		0x8a: newInstruction("ADC A,D", 1, 4, adcAD),
@@ -175,7 +188,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0x8c: newInstruction("ADC A,H", 1, 4, adcAH),
 		0x8d: newInstruction("ADC A,L", 1, 4, adcAL),
 		0x8e: newInstruction("ADC A,(HL)", 1, 8, adcAHL),
-		0x8f: newInstruction("ADC A,A", 1, 8, adcAA),
+		0x8f: newInstruction("ADC A,A", 1, 4, adcAA),
 
 		0x90: newInstruction("SUB B", 1, 4, subB),
 		0x91: newInstruction("SUB C", 1, 4, subC),
@@ -183,7 +196,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0x93: newInstruction("SUB E", 1, 4, subE),
 		0x94: newInstruction("SUB H", 1, 4, subH),
 		0x95: newInstruction("SUB L", 1, 4, subL),
-		0x96: newInstruction("SUB (HL)", 1, 4, subHl),
+		0x96: newInstruction("SUB (HL)", 1, 8, subHl),
 		0x97: newInstruction("SUB A", 1, 4, subA),
 		0x98: newInstruction("SBC A,B", 1, 4, sbcAB),
 		0x99: newInstruction("SBC A,C", 1, 4, sbcAC),
@@ -191,7 +204,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0x9b: newInstruction("SBC A,E", 1, 4, sbcAE),
 		0x9c: newInstruction("SBC A,H", 1, 4, sbcAH),
 		0x9d: newInstruction("SBC A,L", 1, 4, sbcAL),
-		0x9e: newInstruction("SBC A,(HL)", 1, 4, sbcAHL),
+		0x9e: newInstruction("SBC A,(HL)", 1, 8, sbcAHL),
 		0x9f: newInstruction("SBC A,A", 1, 4, sbcAA),
 
 		0xa0: newInstruction("AND B", 1, 4, andB),
@@ -200,7 +213,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0xa3: newInstruction("AND E", 1, 4, andE),
 		0xa4: newInstruction("AND H", 1, 4, andH),
 		0xa5: newInstruction("AND L", 1, 4, andL),
-		0xa6: newInstruction("AND (HL)", 1, 4, andHL),
+		0xa6: newInstruction("AND (HL)", 1, 8, andHL),
 		0xa7: newInstruction("AND A", 1, 4, andA),
 		0xa8: newInstruction("XOR B", 1, 4, xorB),
 		0xa9: newInstruction("XOR C", 1, 4, xorC),
@@ -217,7 +230,7 @@ func createInstructionMap() *map[uint8]*instruction {
 		0xb3: newInstruction("OR E", 1, 4, orE),
 		0xb4: newInstruction("OR H", 1, 4, orH),
 		0xb5: newInstruction("OR L", 1, 4, orL),
-		0xb6: newInstruction("OR (HL)", 1, 4, orHL),
+		0xb6: newInstruction("OR (HL)", 1, 8, orHL),
 		0xb7: newInstruction("OR A", 1, 4, orA),
 		0xb8: newInstruction("CP B", 1, 4, cpB),
 		0xb9: newInstruction("CP C", 1, 4, cpC),
@@ -241,14 +254,14 @@ func createInstructionMap() *map[uint8]*instruction {
 		0xca: newConditionalInstruction("JP Z,a16", 3, 16, 12, jpZa16),
 		0xcb: newInstruction("CB", 1, 4, nil),
 		0xcc: newConditionalInstruction("CALL Z,a16", 3, 24, 12, callZa16),
-		0xcd: newInstruction("CALL a16", 3, 12, callNn),
+		0xcd: newInstruction("CALL a16", 3, 24, callNn),
 		0xce: newInstruction("ADC A,d8", 2, 8, adcAd8),
 		0xcf: newInstruction("RST 08", 1, 16, rst08),
 
 		0xd0: newConditionalInstruction("RET NC", 1, 20, 8, retNC),
 		0xd1: newInstruction("POP DE", 1, 12, popDe),
 		0xd2: newConditionalInstruction("JP NC,a16", 3, 16, 12, jpNcA16),
-		// 0xd3: No instruction
+		0xd3: newInstruction("ILLEGAL", 1, 4, illegal),
 		0xd4: newConditionalInstruction("CALL NC, a16", 3, 24, 12, callNCa16),
 		0xd5: newInstruction("PUSH DE", 1, 16, pushDe),
 		0xd6: newInstruction("SUB d8", 2, 8, subD8),
@@ -256,37 +269,49 @@ func createInstructionMap() *map[uint8]*instruction {
 		0xd8: newConditionalInstruction("RET C", 1, 20, 8, retC),
 		0xd9: newInstruction("RETI", 1, 16, reti),
 		0xda: newConditionalInstruction("JP C,a16", 3, 16, 12, jpCa16),
-		// 0xdb: No instruction
+		0xdb: newInstruction("ILLEGAL", 1, 4, illegal),
 		0xdc: newConditionalInstruction("CALL C,a16", 3, 24, 12, callCa16),
-		// 0xdd: No instruction
+		0xdd: newInstruction("ILLEGAL", 1, 4, illegal),
 		0xde: newInstruction("SBC A,d8", 2, 8, sbcAd8),
 		0xdf: newInstruction("RST 18H", 1, 16, rst18),
 
 		0xe0: newInstruction("LDH (a8),A", 2, 12, ldhA8A),
 		0xe1: newInstruction("POP HL", 1, 12, popHl),
 		0xe2: newInstruction("LD (C),A", 1, 8, ldACA),
-		0xe5: newInstruction("PUSH HL", 1, 8, pushHl),
+		0xe3: newInstruction("ILLEGAL", 1, 4, illegal),
+		0xe4: newInstruction("ILLEGAL", 1, 4, illegal),
+		0xe5: newInstruction("PUSH HL", 1, 16, pushHl),
 		0xe6: newInstruction("AND d8", 2, 8, andd8),
 		0xe7: newInstruction("RST 20", 1, 16, rst20),
-		0xe9: newInstruction("JP (HL)", 1, 8, jphl),
+		0xe8: newInstruction("ADD SP,r8", 2, 16, addSPr8),
+		0xe9: newInstruction("JP (HL)", 1, 4, jphl),
 		0xea: newInstruction("LD (a16),A", 3, 16, ldA16A),
+		0xeb: newInstruction("ILLEGAL", 1, 4, illegal),
+		0xec: newInstruction("ILLEGAL", 1, 4, illegal),
+		0xed: newInstruction("ILLEGAL", 1, 4, illegal),
 		0xee: newInstruction("XOR d8", 2, 8, xord8),
 		0xef: newInstruction("RST 28H", 1, 16, rst28),
 
 		0xf0: newInstruction("LDH A,(a8)", 2, 12, ldAA8),
 		0xf1: newInstruction("POP AF", 1, 12, popAf),
+		0xf2: newInstruction("LD A,(C)", 1, 8, ldAC8),
 		0xf3: newInstruction("DI", 1, 4, di),
+		0xf4: newInstruction("ILLEGAL", 1, 4, illegal),
 		0xf5: newInstruction("PUSH AF", 1, 16, pushAf),
 		0xf6: newInstruction("OR d8", 2, 8, ord8),
+		0xf7: newInstruction("RST 30", 1, 16, rst30),
 		0xf8: newInstruction("LD HL,SP+r8", 2, 12, ldHLSPr8),
 		0xf9: newInstruction("LD SP,HL", 1, 8, ldSPHl),
 		0xfa: newInstruction("LD A,(a16)", 3, 16, ldAa16),
 		0xfb: newInstruction("EI", 1, 4, ei),
+		0xfc: newInstruction("ILLEGAL", 1, 4, illegal),
+		0xfd: newInstruction("ILLEGAL", 1, 4, illegal),
 		0xfe: newInstruction("CP d8", 2, 8, cpD8),
+		0xff: newInstruction("RST 38", 1, 16, rst38),
 	}
 }
 
-func newConditionalInstruction(name string, length int, actionDuration int, noopDuration int, fp func(mem *memory, reg *register, instr *instruction) int) *instruction {
+func newConditionalInstruction(name string, length int, actionDuration int, noopDuration int, fp instructionExecutor) *instruction {
 	return &instruction{
 		name:           name,
 		bytes:          length,
@@ -296,7 +321,7 @@ func newConditionalInstruction(name string, length int, actionDuration int, noop
 	}
 }
 
-func newInstruction(name string, length int, duration int, fp func(mem *memory, reg *register, instr *instruction) int) *instruction {
+func newInstruction(name string, length int, duration int, fp instructionExecutor) *instruction {
 	return newConditionalInstruction(name, length, duration, 0, fp)
 }
 
@@ -315,7 +340,7 @@ func incRegister(br *uint8, reg *register) {
 	*br += uint8(1)
 	reg.setZ(*br == 0)
 	reg.setN(false)
-	reg.setH(before == 0xf)
+	reg.setH(before&0xf == 0xf)
 }
 
 func decRegister(br *uint8, reg *register) {
@@ -330,231 +355,265 @@ func subRegister(reg *register, val uint8) {
 	reg.A = reg.A - val
 	reg.setZ(reg.A == 0)
 	reg.setN(true)
-	reg.setH(!(before > 0xf && reg.A <= 0xf))
+	reg.setH(before&0xf < val&0xf)
 	reg.setC(before < val)
 }
 
-func callNn(mem *memory, reg *register, instr *instruction) int {
+func addRegister(reg *register, val uint8) {
+	result := uint16(reg.A) + uint16(val)
+
+	reg.setZ(uint8(result) == 0)
+	reg.setN(false)
+	reg.setH(reg.A&0xf+val&0xf > 0xf)
+	reg.setC(result > 0xff)
+
+	reg.A = uint8(result)
+}
+
+// Adds val to HL. The Z flag is not affected.
+func addHLRegister(reg *register, val uint16) {
+	hl := reg.readDuo(REG_HL)
+	result := uint32(hl) + uint32(val)
+
+	reg.setN(false)
+	reg.setH(hl&0xfff+val&0xfff > 0xfff)
+	reg.setC(result > 0xffff)
+
+	reg.writeDuo(REG_HL, uint16(result))
+}
+
+// Returns SP plus the signed argument. The flags are set by the unsigned addition of the lower bytes.
+func addSPSigned(reg *register, arg uint8) uint16 {
+	result := uint16(int(reg.SP) + int(int8(arg)))
+
+	reg.setZ(false)
+	reg.setN(false)
+	reg.setH(reg.SP&0xf+uint16(arg)&0xf > 0xf)
+	reg.setC(reg.SP&0xff+uint16(arg) > 0xff)
+
+	return result
+}
+
+func callNn(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth++
 	pushStack16(mem, reg, reg.PC+uint16(instr.bytes))
 	reg.PC = readArgHalfword(mem, reg)
-	return instr.durationAction
+	return true
 }
 
-func jrNZr8(mem *memory, reg *register, instr *instruction) int {
+func jrNZr8(mem *memory, reg *register, instr *instruction) bool {
 	var n = int(int8(readArgByte(mem, reg)))
 	reg.incPC(instr.bytes)
 	if !reg.isZ() {
 		reg.PC = uint16(int(reg.PC) + n)
-		return instr.durationAction
+		return true
 	}
-	return instr.durationNoop
+	return false
 }
 
-func jpCa16(mem *memory, reg *register, instr *instruction) int {
+func jpCa16(mem *memory, reg *register, instr *instruction) bool {
 	if reg.isC() {
 		reg.PC = readArgHalfword(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func jpZa16(mem *memory, reg *register, instr *instruction) int {
+func jpZa16(mem *memory, reg *register, instr *instruction) bool {
 	if reg.isZ() {
 		reg.PC = readArgHalfword(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func jpNzA16(mem *memory, reg *register, instr *instruction) int {
+func jpNzA16(mem *memory, reg *register, instr *instruction) bool {
 	if !reg.isZ() {
 		reg.PC = readArgHalfword(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func jpNcA16(mem *memory, reg *register, instr *instruction) int {
+func jpNcA16(mem *memory, reg *register, instr *instruction) bool {
 	if !reg.isC() {
 		reg.PC = readArgHalfword(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func incB(_ *memory, reg *register, instr *instruction) int {
+func incB(_ *memory, reg *register, instr *instruction) bool {
 	incRegister(&reg.B, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incC(_ *memory, reg *register, instr *instruction) int {
+func incC(_ *memory, reg *register, instr *instruction) bool {
 	incRegister(&reg.C, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAd8(mem *memory, reg *register, instr *instruction) int {
+func ldAd8(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = readArgByte(mem, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldADE(mem *memory, reg *register, instr *instruction) int {
+func ldADE(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = mem.read8(reg.readDuo(REG_DE))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldC(mem *memory, reg *register, instr *instruction) int {
+func ldC(mem *memory, reg *register, instr *instruction) bool {
 	reg.C = readArgByte(mem, reg)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldCB(_ *memory, reg *register, instr *instruction) int {
+func ldCB(_ *memory, reg *register, instr *instruction) bool {
 	reg.C = reg.B
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldCC(_ *memory, reg *register, instr *instruction) int {
+func ldCC(_ *memory, reg *register, instr *instruction) bool {
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldCD(_ *memory, reg *register, instr *instruction) int {
+func ldCD(_ *memory, reg *register, instr *instruction) bool {
 	reg.C = reg.D
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldCE(_ *memory, reg *register, instr *instruction) int {
+func ldCE(_ *memory, reg *register, instr *instruction) bool {
 	reg.C = reg.E
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldCH(_ *memory, reg *register, instr *instruction) int {
+func ldCH(_ *memory, reg *register, instr *instruction) bool {
 	reg.C = reg.H
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldCL(_ *memory, reg *register, instr *instruction) int {
+func ldCL(_ *memory, reg *register, instr *instruction) bool {
 	reg.C = reg.L
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDEd16(mem *memory, reg *register, instr *instruction) int {
+func ldDEd16(mem *memory, reg *register, instr *instruction) bool {
 	reg.writeDuo(REG_DE, readArgHalfword(mem, reg))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldSPd16(mem *memory, reg *register, instr *instruction) int {
-	fmt.Printf("Loading into SP (d16): %#04x\n", reg.readDuo(REG_HL))
+func ldSPd16(mem *memory, reg *register, instr *instruction) bool {
 	reg.SP = readArgHalfword(mem, reg)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLd16(mem *memory, reg *register, instr *instruction) int {
+func ldHLd16(mem *memory, reg *register, instr *instruction) bool {
 	reg.writeDuo(REG_HL, readArgHalfword(mem, reg))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLA(mem *memory, reg *register, instr *instruction) int {
+func ldHLA(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.A)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLB(mem *memory, reg *register, instr *instruction) int {
+func ldHLB(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.B)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLC(mem *memory, reg *register, instr *instruction) int {
+func ldHLC(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.C)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLD(mem *memory, reg *register, instr *instruction) int {
+func ldHLD(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.D)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLE(mem *memory, reg *register, instr *instruction) int {
+func ldHLE(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.E)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLH(mem *memory, reg *register, instr *instruction) int {
+func ldHLH(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.H)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLL(mem *memory, reg *register, instr *instruction) int {
+func ldHLL(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.L)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func lddHLA(mem *memory, reg *register, instr *instruction) int {
+func lddHLA(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.A)
 	reg.decrDuo(REG_HL)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAdHL(mem *memory, reg *register, instr *instruction) int {
+func ldAdHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = mem.read8(reg.readDuo(REG_HL))
 	reg.decrDuo(REG_HL)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldhA8A(mem *memory, reg *register, instr *instruction) int {
+func ldhA8A(mem *memory, reg *register, instr *instruction) bool {
 	arg := readArgByte(mem, reg)
 	mem.write8(0xFF00+uint16(arg), reg.A)
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func xord8(mem *memory, reg *register, instr *instruction) int {
+func xord8(mem *memory, reg *register, instr *instruction) bool {
 	reg.A ^= readArgByte(mem, reg)
 
 	reg.setZ(reg.A == 0)
@@ -563,17 +622,17 @@ func xord8(mem *memory, reg *register, instr *instruction) int {
 	reg.setC(false)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func pushBc(mem *memory, reg *register, instr *instruction) int {
+func pushBc(mem *memory, reg *register, instr *instruction) bool {
 	pushStack16(mem, reg, reg.readDuo(REG_BC))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func rla(_ *memory, reg *register, instr *instruction) int {
+func rla(_ *memory, reg *register, instr *instruction) bool {
 	carry := reg.isC()
 	reg.setC(testBit(reg.A, 7))
 
@@ -585,640 +644,574 @@ func rla(_ *memory, reg *register, instr *instruction) int {
 	reg.setN(false)
 	reg.setH(false)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func rlca(_ *memory, reg *register, instr *instruction) int {
-	reg.setC(testBit(reg.A, 7))
-	reg.A <<= 1
+func rlca(_ *memory, reg *register, instr *instruction) bool {
+	var carry bool
+	reg.A, carry = rLeft(reg.A)
+	reg.setC(carry)
 
 	reg.setZ(false)
 	reg.setN(false)
 	reg.setH(false)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func popBc(mem *memory, reg *register, instr *instruction) int {
+func popBc(mem *memory, reg *register, instr *instruction) bool {
 	reg.writeDuo(REG_BC, popStack16(mem, reg))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decB(_ *memory, reg *register, instr *instruction) int {
+func decB(_ *memory, reg *register, instr *instruction) bool {
 	decRegister(&reg.B, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldCA(_ *memory, reg *register, instr *instruction) int {
+func ldCA(_ *memory, reg *register, instr *instruction) bool {
 	reg.C = reg.A
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBd8(mem *memory, reg *register, instr *instruction) int {
+func ldBd8(mem *memory, reg *register, instr *instruction) bool {
 	reg.B = readArgByte(mem, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLPA(mem *memory, reg *register, instr *instruction) int {
+func ldHLPA(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), reg.A)
 	reg.incrDuo(REG_HL)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incHL(_ *memory, reg *register, instr *instruction) int {
+func incHL(_ *memory, reg *register, instr *instruction) bool {
 	reg.incrDuo(REG_HL)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decHl(mem *memory, reg *register, instr *instruction) int {
+func decHl(mem *memory, reg *register, instr *instruction) bool {
 	addr := reg.readDuo(REG_HL)
-	val := mem.read8(addr) - 1
+	val := mem.read8(addr)
+	decRegister(&val, reg)
 	mem.write8(addr, val)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incDE(_ *memory, reg *register, instr *instruction) int {
+func incDE(_ *memory, reg *register, instr *instruction) bool {
 	reg.incrDuo(REG_DE)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incBC(_ *memory, reg *register, instr *instruction) int {
+func incBC(_ *memory, reg *register, instr *instruction) bool {
 	reg.incrDuo(REG_BC)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ret(mem *memory, reg *register, instr *instruction) int {
+func ret(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth--
 	reg.PC = popStack16(mem, reg)
-	return instr.durationAction
+	return true
 }
 
-func ldAE(_ *memory, reg *register, instr *instruction) int {
+func ldAE(_ *memory, reg *register, instr *instruction) bool {
 	reg.A = reg.E
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpD8(mem *memory, reg *register, instr *instruction) int {
+func cpD8(mem *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpB(_ *memory, reg *register, instr *instruction) int {
+func cpB(_ *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, reg.B)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpC(_ *memory, reg *register, instr *instruction) int {
+func cpC(_ *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, reg.C)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpD(_ *memory, reg *register, instr *instruction) int {
+func cpD(_ *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, reg.D)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpE(_ *memory, reg *register, instr *instruction) int {
+func cpE(_ *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, reg.E)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpH(_ *memory, reg *register, instr *instruction) int {
+func cpH(_ *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, reg.H)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpL(_ *memory, reg *register, instr *instruction) int {
+func cpL(_ *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, reg.L)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpHL(mem *memory, reg *register, instr *instruction) int {
+func cpHL(mem *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, mem.read8(reg.readDuo(REG_HL)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func cpA(_ *memory, reg *register, instr *instruction) int {
+func cpA(_ *memory, reg *register, instr *instruction) bool {
 	compareRegister(reg, reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
 func compareRegister(reg *register, val uint8) {
 	reg.setZ(reg.A == val)
 	reg.setN(true)
-	reg.setH(true)
+	reg.setH(reg.A&0xf < val&0xf)
 	reg.setC(reg.A < val)
 }
 
-func ldA16A(mem *memory, reg *register, instr *instruction) int {
+func ldA16A(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(readArgHalfword(mem, reg), reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decA(_ *memory, reg *register, instr *instruction) int {
+func decA(_ *memory, reg *register, instr *instruction) bool {
 	decRegister(&reg.A, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decL(_ *memory, reg *register, instr *instruction) int {
+func decL(_ *memory, reg *register, instr *instruction) bool {
 	decRegister(&reg.L, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decH(_ *memory, reg *register, instr *instruction) int {
+func decH(_ *memory, reg *register, instr *instruction) bool {
 	decRegister(&reg.H, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func jrZr8(mem *memory, reg *register, instr *instruction) int {
+func jrZr8(mem *memory, reg *register, instr *instruction) bool {
 	arg := int8(readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
 	if reg.isZ() {
 		reg.PC = uint16(int(reg.PC) + int(arg))
-		return instr.durationAction
+		return true
 	}
-	return instr.durationNoop
+	return false
 }
 
-func jrCr8(mem *memory, reg *register, instr *instruction) int {
+func jrCr8(mem *memory, reg *register, instr *instruction) bool {
 	arg := int8(readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
 	if reg.isC() {
 		reg.PC = uint16(int(reg.PC) + int(arg))
-		return instr.durationAction
+		return true
 	}
-	return instr.durationNoop
+	return false
 }
 
-func jrNCr8(mem *memory, reg *register, instr *instruction) int {
+func jrNCr8(mem *memory, reg *register, instr *instruction) bool {
 	arg := int8(readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
 	if !reg.isC() {
 		reg.PC = uint16(int(reg.PC) + int(arg))
-		return instr.durationAction
+		return true
 	}
-	return instr.durationNoop
+	return false
 }
 
-func decC(_ *memory, reg *register, instr *instruction) int {
+func decC(_ *memory, reg *register, instr *instruction) bool {
 	decRegister(&reg.C, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLD8(mem *memory, reg *register, instr *instruction) int {
+func ldLD8(mem *memory, reg *register, instr *instruction) bool {
 	reg.L = readArgByte(mem, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func jrr8(mem *memory, reg *register, instr *instruction) int {
+func jrr8(mem *memory, reg *register, instr *instruction) bool {
 	oldPc := reg.PC
 	arg := int8(readArgByte(mem, reg))
 	reg.PC = uint16(int(oldPc) + int(arg))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHA(_ *memory, reg *register, instr *instruction) int {
+func ldHA(_ *memory, reg *register, instr *instruction) bool {
 	reg.H = reg.A
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHC(_ *memory, reg *register, instr *instruction) int {
+func ldHC(_ *memory, reg *register, instr *instruction) bool {
 	reg.H = reg.C
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDA(_ *memory, reg *register, instr *instruction) int {
+func ldDA(_ *memory, reg *register, instr *instruction) bool {
 	reg.D = reg.A
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDB(_ *memory, reg *register, instr *instruction) int {
+func ldDB(_ *memory, reg *register, instr *instruction) bool {
 	reg.D = reg.B
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDC(_ *memory, reg *register, instr *instruction) int {
+func ldDC(_ *memory, reg *register, instr *instruction) bool {
 	reg.D = reg.C
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDD(_ *memory, reg *register, instr *instruction) int {
+func ldDD(_ *memory, reg *register, instr *instruction) bool {
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDE(_ *memory, reg *register, instr *instruction) int {
+func ldDE(_ *memory, reg *register, instr *instruction) bool {
 	reg.D = reg.E
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDH(_ *memory, reg *register, instr *instruction) int {
+func ldDH(_ *memory, reg *register, instr *instruction) bool {
 	reg.D = reg.H
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDL(_ *memory, reg *register, instr *instruction) int {
+func ldDL(_ *memory, reg *register, instr *instruction) bool {
 	reg.D = reg.L
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldEA(_ *memory, reg *register, instr *instruction) int {
+func ldEA(_ *memory, reg *register, instr *instruction) bool {
 	reg.E = reg.A
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldEB(_ *memory, reg *register, instr *instruction) int {
+func ldEB(_ *memory, reg *register, instr *instruction) bool {
 	reg.E = reg.B
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldEC(_ *memory, reg *register, instr *instruction) int {
+func ldEC(_ *memory, reg *register, instr *instruction) bool {
 	reg.E = reg.C
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldED(_ *memory, reg *register, instr *instruction) int {
+func ldED(_ *memory, reg *register, instr *instruction) bool {
 	reg.E = reg.D
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldEE(_ *memory, reg *register, instr *instruction) int {
+func ldEE(_ *memory, reg *register, instr *instruction) bool {
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldEH(_ *memory, reg *register, instr *instruction) int {
+func ldEH(_ *memory, reg *register, instr *instruction) bool {
 	reg.E = reg.H
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldEL(_ *memory, reg *register, instr *instruction) int {
+func ldEL(_ *memory, reg *register, instr *instruction) bool {
 	reg.E = reg.L
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldEd8(mem *memory, reg *register, instr *instruction) int {
+func ldEd8(mem *memory, reg *register, instr *instruction) bool {
 	reg.E = readArgByte(mem, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAA8(mem *memory, reg *register, instr *instruction) int {
+func ldAA8(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = mem.read8(0xFF00 + uint16(readArgByte(mem, reg)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decE(_ *memory, reg *register, instr *instruction) int {
+func decE(_ *memory, reg *register, instr *instruction) bool {
 	decRegister(&reg.E, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incH(_ *memory, reg *register, instr *instruction) int {
+func incH(_ *memory, reg *register, instr *instruction) bool {
 	incRegister(&reg.H, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAH(_ *memory, reg *register, instr *instruction) int {
+func ldAH(_ *memory, reg *register, instr *instruction) bool {
 	reg.A = reg.H
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subB(_ *memory, reg *register, instr *instruction) int {
+func subB(_ *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, reg.B)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subC(_ *memory, reg *register, instr *instruction) int {
+func subC(_ *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, reg.C)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subD(_ *memory, reg *register, instr *instruction) int {
+func subD(_ *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, reg.D)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subE(_ *memory, reg *register, instr *instruction) int {
+func subE(_ *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, reg.E)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subH(_ *memory, reg *register, instr *instruction) int {
+func subH(_ *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, reg.H)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subL(_ *memory, reg *register, instr *instruction) int {
+func subL(_ *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, reg.L)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subHl(mem *memory, reg *register, instr *instruction) int {
+func subHl(mem *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, mem.read8(reg.readDuo(REG_HL)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subA(_ *memory, reg *register, instr *instruction) int {
+func subA(_ *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func subD8(mem *memory, reg *register, instr *instruction) int {
+func subD8(mem *memory, reg *register, instr *instruction) bool {
 	subRegister(reg, readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decD(_ *memory, reg *register, instr *instruction) int {
+func decD(_ *memory, reg *register, instr *instruction) bool {
 	decRegister(&reg.D, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDd8(mem *memory, reg *register, instr *instruction) int {
+func ldDd8(mem *memory, reg *register, instr *instruction) bool {
 	reg.D = readArgByte(mem, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHd8(mem *memory, reg *register, instr *instruction) int {
+func ldHd8(mem *memory, reg *register, instr *instruction) bool {
 	reg.H = readArgByte(mem, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAL(_ *memory, reg *register, instr *instruction) int {
+func ldAL(_ *memory, reg *register, instr *instruction) bool {
 	reg.A = reg.L
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAA(_ *memory, reg *register, instr *instruction) int {
+func ldAA(_ *memory, reg *register, instr *instruction) bool {
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAB(_ *memory, reg *register, instr *instruction) int {
+func ldAB(_ *memory, reg *register, instr *instruction) bool {
 	reg.A = reg.B
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAC(_ *memory, reg *register, instr *instruction) int {
+func ldAC(_ *memory, reg *register, instr *instruction) bool {
 	reg.A = reg.C
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAD(_ *memory, reg *register, instr *instruction) int {
+func ldAD(_ *memory, reg *register, instr *instruction) bool {
 	reg.A = reg.D
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAHL(mem *memory, reg *register, instr *instruction) int {
-	value := mem.read8(reg.readDuo(REG_HL))
-
-	reg.setZ(reg.A+value == 0)
-	reg.setN(false)
-	reg.setH(reg.A&0xf+value&0xf < reg.A&0xf)
-	reg.setC(uint16(reg.A)+uint16(value) > 0xff)
-
-	reg.A = reg.A + value
-
+func addAHL(mem *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, mem.read8(reg.readDuo(REG_HL)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAA(_ *memory, reg *register, instr *instruction) int {
-	var val = uint16(reg.A) + uint16(reg.A)
-
-	reg.setZ(val == 0)
-	reg.setN(false)
-	reg.setH((reg.A+reg.A)&0xf < reg.A&0xf)
-	reg.setC(val > 0xff)
-
-	reg.A = uint8(val)
-
+func addAA(_ *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAB(_ *memory, reg *register, instr *instruction) int {
-	var val = uint16(reg.A) + uint16(reg.B)
-
-	reg.setZ(val == 0)
-	reg.setN(false)
-	reg.setH(reg.A&0xf+reg.B&0xf > 0xf)
-	reg.setC(val > 0xff)
-
-	reg.A = uint8(val)
-
+func addAB(_ *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, reg.B)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAC(_ *memory, reg *register, instr *instruction) int {
-	var val = uint16(reg.A) + uint16(reg.C)
-
-	reg.setZ(val == 0)
-	reg.setN(false)
-	reg.setH(reg.A&0xf+reg.C&0xf > 0xf)
-	reg.setC(val > 0xff)
-
-	reg.A = uint8(val)
-
+func addAC(_ *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, reg.C)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAD(_ *memory, reg *register, instr *instruction) int {
-	var val = uint16(reg.A) + uint16(reg.D)
-
-	reg.setZ(val == 0)
-	reg.setN(false)
-	reg.setH(reg.A&0xf+reg.D&0xf > 0xf)
-	reg.setC(val > 0xff)
-
-	reg.A = uint8(val)
-
+func addAD(_ *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, reg.D)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAE(_ *memory, reg *register, instr *instruction) int {
-	var val = uint16(reg.A) + uint16(reg.E)
-
-	reg.setZ(val == 0)
-	reg.setN(false)
-	reg.setH(reg.A&0xf+reg.E&0xf > 0xf)
-	reg.setC(val > 0xff)
-
-	reg.A = uint8(val)
-
+func addAE(_ *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, reg.E)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAH(_ *memory, reg *register, instr *instruction) int {
-	var val = uint16(reg.A) + uint16(reg.H)
-
-	reg.setZ(val == 0)
-	reg.setN(false)
-	reg.setH(reg.A&0xf+reg.H&0xf > 0xf)
-	reg.setC(val > 0xff)
-
-	reg.A = uint8(val)
-
+func addAH(_ *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, reg.H)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAL(_ *memory, reg *register, instr *instruction) int {
-	var val = uint16(reg.A) + uint16(reg.L)
-
-	reg.setZ(val == 0)
-	reg.setN(false)
-	reg.setH(reg.A&0xf+reg.L&0xf > 0xf)
-	reg.setC(val > 0xff)
-
-	reg.A = uint8(val)
-
+func addAL(_ *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, reg.L)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addAd8(mem *memory, reg *register, instr *instruction) int {
-	arg := readArgByte(mem, reg)
-	var val = reg.A + arg
-
-	reg.setZ(reg.A == 0)
-	reg.setN(false)
-	reg.setH(reg.A&0xf+arg&0xf > 0xf)
-	reg.setC(val > 0xff)
-
-	reg.A = uint8(val)
-
+func addAd8(mem *memory, reg *register, instr *instruction) bool {
+	addRegister(reg, readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func noop(_ *memory, reg *register, instr *instruction) int {
+func noop(_ *memory, reg *register, instr *instruction) bool {
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func jpnn(mem *memory, reg *register, instr *instruction) int {
+func jpnn(mem *memory, reg *register, instr *instruction) bool {
 	reg.PC = readArgHalfword(mem, reg)
-	return instr.durationAction
+	return true
 }
 
-func di(_ *memory, reg *register, instr *instruction) int {
+func di(_ *memory, reg *register, instr *instruction) bool {
+	// Unlike EI, DI takes effect immediately and cancels a pending EI
+	reg.interruptMaster = false
+	reg.interruptEnableScheduled = false
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ei(_ *memory, reg *register, instr *instruction) int {
+func ei(_ *memory, reg *register, instr *instruction) bool {
+	// Interrupts are enabled after the next instruction, see Gameboy.Step
+	reg.interruptEnableScheduled = true
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHLd8(mem *memory, reg *register, instr *instruction) int {
+func ldHLd8(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_HL), readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAHLP(mem *memory, reg *register, instr *instruction) int {
+func ldAHLP(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = mem.read8(reg.readDuo(REG_HL))
 	reg.incrDuo(REG_HL)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBCnn(mem *memory, reg *register, instr *instruction) int {
+func ldBCnn(mem *memory, reg *register, instr *instruction) bool {
 	arg := readArgHalfword(mem, reg)
 	reg.writeDuo(REG_BC, arg)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decBC(_ *memory, reg *register, instr *instruction) int {
+func decBC(_ *memory, reg *register, instr *instruction) bool {
 	reg.decrDuo(REG_BC)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decDE(_ *memory, reg *register, instr *instruction) int {
+func decDE(_ *memory, reg *register, instr *instruction) bool {
 	reg.decrDuo(REG_DE)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decHL(_ *memory, reg *register, instr *instruction) int {
+func decHL(_ *memory, reg *register, instr *instruction) bool {
 	reg.decrDuo(REG_HL)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func orHL(mem *memory, reg *register, instr *instruction) int {
+func orHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = mem.read8(reg.readDuo(REG_HL)) | reg.A
 
 	reg.setZ(reg.A == 0)
@@ -1226,76 +1219,76 @@ func orHL(mem *memory, reg *register, instr *instruction) int {
 	reg.setH(false)
 	reg.setC(false)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func pushAf(mem *memory, reg *register, instr *instruction) int {
+func pushAf(mem *memory, reg *register, instr *instruction) bool {
 	pushStack16(mem, reg, reg.readDuo(REG_AF))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func pushDe(mem *memory, reg *register, instr *instruction) int {
+func pushDe(mem *memory, reg *register, instr *instruction) bool {
 	pushStack16(mem, reg, reg.readDuo(REG_DE))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func pushHl(mem *memory, reg *register, instr *instruction) int {
+func pushHl(mem *memory, reg *register, instr *instruction) bool {
 	pushStack16(mem, reg, reg.readDuo(REG_HL))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andB(_ *memory, reg *register, instr *instruction) int {
+func andB(_ *memory, reg *register, instr *instruction) bool {
 	andReg(reg, reg.B)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andC(_ *memory, reg *register, instr *instruction) int {
+func andC(_ *memory, reg *register, instr *instruction) bool {
 	andReg(reg, reg.C)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andD(_ *memory, reg *register, instr *instruction) int {
+func andD(_ *memory, reg *register, instr *instruction) bool {
 	andReg(reg, reg.D)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andE(_ *memory, reg *register, instr *instruction) int {
+func andE(_ *memory, reg *register, instr *instruction) bool {
 	andReg(reg, reg.E)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andH(_ *memory, reg *register, instr *instruction) int {
+func andH(_ *memory, reg *register, instr *instruction) bool {
 	andReg(reg, reg.H)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andL(_ *memory, reg *register, instr *instruction) int {
+func andL(_ *memory, reg *register, instr *instruction) bool {
 	andReg(reg, reg.L)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andHL(mem *memory, reg *register, instr *instruction) int {
+func andHL(mem *memory, reg *register, instr *instruction) bool {
 	andReg(reg, mem.read8(reg.readDuo(REG_HL)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andA(_ *memory, reg *register, instr *instruction) int {
+func andA(_ *memory, reg *register, instr *instruction) bool {
 	andReg(reg, reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
 func andReg(reg *register, val uint8) {
@@ -1307,159 +1300,163 @@ func andReg(reg *register, val uint8) {
 	reg.setC(false)
 }
 
-func retNz(mem *memory, reg *register, instr *instruction) int {
+func retNz(mem *memory, reg *register, instr *instruction) bool {
 	if !reg.isZ() {
 		mem.depth--
 		reg.PC = popStack16(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func callCa16(mem *memory, reg *register, instr *instruction) int {
+func callCa16(mem *memory, reg *register, instr *instruction) bool {
 	if reg.isC() {
 		mem.depth++
 		pushStack16(mem, reg, reg.PC+uint16(3))
 		reg.PC = readArgHalfword(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func callZa16(mem *memory, reg *register, instr *instruction) int {
+func callZa16(mem *memory, reg *register, instr *instruction) bool {
 	if reg.isZ() {
 		mem.depth++
 		pushStack16(mem, reg, reg.PC+uint16(3))
 		reg.PC = readArgHalfword(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func callNZa16(mem *memory, reg *register, instr *instruction) int {
+func callNZa16(mem *memory, reg *register, instr *instruction) bool {
 	if !reg.isZ() {
 		mem.depth++
 		pushStack16(mem, reg, reg.PC+uint16(3))
 		reg.PC = readArgHalfword(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func callNCa16(mem *memory, reg *register, instr *instruction) int {
+func callNCa16(mem *memory, reg *register, instr *instruction) bool {
 	if !reg.isC() {
 		mem.depth++
 		pushStack16(mem, reg, reg.PC+uint16(3))
 		reg.PC = readArgHalfword(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func ldAa16(mem *memory, reg *register, instr *instruction) int {
+func ldAa16(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = mem.read8(readArgHalfword(mem, reg))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func retZ(mem *memory, reg *register, instr *instruction) int {
+func retZ(mem *memory, reg *register, instr *instruction) bool {
 	if reg.isZ() {
 		mem.depth--
 		reg.PC = popStack16(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func retNC(mem *memory, reg *register, instr *instruction) int {
+func retNC(mem *memory, reg *register, instr *instruction) bool {
 	if !reg.isC() {
 		mem.depth--
 		reg.PC = popStack16(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func retC(mem *memory, reg *register, instr *instruction) int {
+func retC(mem *memory, reg *register, instr *instruction) bool {
 	if reg.isC() {
 		mem.depth--
 		reg.PC = popStack16(mem, reg)
-		return instr.durationAction
+		return true
 	}
 	reg.incPC(instr.bytes)
-	return instr.durationNoop
+	return false
 }
 
-func incAHL(mem *memory, reg *register, instr *instruction) int {
-	val := mem.read8(reg.readDuo(REG_HL)) + 1
-	mem.write8(reg.readDuo(REG_HL), val)
+func incAHL(mem *memory, reg *register, instr *instruction) bool {
+	addr := reg.readDuo(REG_HL)
+	val := mem.read8(addr)
+	incRegister(&val, reg)
+	mem.write8(addr, val)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incA(_ *memory, reg *register, instr *instruction) int {
+func incA(_ *memory, reg *register, instr *instruction) bool {
 	incRegister(&reg.A, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incD(_ *memory, reg *register, instr *instruction) int {
+func incD(_ *memory, reg *register, instr *instruction) bool {
 	incRegister(&reg.D, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incE(_ *memory, reg *register, instr *instruction) int {
+func incE(_ *memory, reg *register, instr *instruction) bool {
 	incRegister(&reg.E, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func popAf(mem *memory, reg *register, instr *instruction) int {
-	reg.writeDuo(REG_AF, popStack16(mem, reg))
+func popAf(mem *memory, reg *register, instr *instruction) bool {
+	// The lower four bits of F do not exist and always read as zero
+	reg.writeDuo(REG_AF, popStack16(mem, reg)&0xfff0)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func popDe(mem *memory, reg *register, instr *instruction) int {
+func popDe(mem *memory, reg *register, instr *instruction) bool {
 	reg.writeDuo(REG_DE, popStack16(mem, reg))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func popHl(mem *memory, reg *register, instr *instruction) int {
+func popHl(mem *memory, reg *register, instr *instruction) bool {
 	reg.writeDuo(REG_HL, popStack16(mem, reg))
 	// Does not affect flags
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func reti(mem *memory, reg *register, instr *instruction) int {
+func reti(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth--
 	reg.PC = popStack16(mem, reg)
-	// Does not affect flags
-	return instr.durationAction
+	// Unlike EI, RETI enables interrupts immediately
+	reg.interruptMaster = true
+	return true
 }
 
-func cpl(_ *memory, reg *register, instr *instruction) int {
+func cpl(_ *memory, reg *register, instr *instruction) bool {
 	reg.A = ^reg.A
 	reg.setN(true)
 	reg.setH(true)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func andd8(mem *memory, reg *register, instr *instruction) int {
+func andd8(mem *memory, reg *register, instr *instruction) bool {
 	arg := readArgByte(mem, reg)
 	reg.A = arg & reg.A
 	reg.setZ(reg.A == 0)
@@ -1467,97 +1464,97 @@ func andd8(mem *memory, reg *register, instr *instruction) int {
 	reg.setH(true)
 	reg.setC(false)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBA(_ *memory, reg *register, instr *instruction) int {
+func ldBA(_ *memory, reg *register, instr *instruction) bool {
 	reg.B = reg.A
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBB(_ *memory, reg *register, instr *instruction) int {
+func ldBB(_ *memory, reg *register, instr *instruction) bool {
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBC(_ *memory, reg *register, instr *instruction) int {
+func ldBC(_ *memory, reg *register, instr *instruction) bool {
 	reg.B = reg.C
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBD(_ *memory, reg *register, instr *instruction) int {
+func ldBD(_ *memory, reg *register, instr *instruction) bool {
 	reg.B = reg.D
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBE(_ *memory, reg *register, instr *instruction) int {
+func ldBE(_ *memory, reg *register, instr *instruction) bool {
 	reg.B = reg.E
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBH(_ *memory, reg *register, instr *instruction) int {
+func ldBH(_ *memory, reg *register, instr *instruction) bool {
 	reg.B = reg.H
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBL(_ *memory, reg *register, instr *instruction) int {
+func ldBL(_ *memory, reg *register, instr *instruction) bool {
 	reg.B = reg.L
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func orA(_ *memory, reg *register, instr *instruction) int {
+func orA(_ *memory, reg *register, instr *instruction) bool {
 	orRegister(reg, reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func orB(_ *memory, reg *register, instr *instruction) int {
+func orB(_ *memory, reg *register, instr *instruction) bool {
 	orRegister(reg, reg.B)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func orC(_ *memory, reg *register, instr *instruction) int {
+func orC(_ *memory, reg *register, instr *instruction) bool {
 	orRegister(reg, reg.C)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func orD(_ *memory, reg *register, instr *instruction) int {
+func orD(_ *memory, reg *register, instr *instruction) bool {
 	orRegister(reg, reg.D)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func orE(_ *memory, reg *register, instr *instruction) int {
+func orE(_ *memory, reg *register, instr *instruction) bool {
 	orRegister(reg, reg.E)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func orH(_ *memory, reg *register, instr *instruction) int {
+func orH(_ *memory, reg *register, instr *instruction) bool {
 	orRegister(reg, reg.H)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func orL(_ *memory, reg *register, instr *instruction) int {
+func orL(_ *memory, reg *register, instr *instruction) bool {
 	orRegister(reg, reg.L)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ord8(mem *memory, reg *register, instr *instruction) int {
+func ord8(mem *memory, reg *register, instr *instruction) bool {
 	orRegister(reg, readArgByte(mem, reg))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
 func orRegister(reg *register, val uint8) {
@@ -1569,52 +1566,52 @@ func orRegister(reg *register, val uint8) {
 	reg.setC(false)
 }
 
-func xorB(_ *memory, reg *register, instr *instruction) int {
+func xorB(_ *memory, reg *register, instr *instruction) bool {
 	xorReg(reg, reg.B)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func xorC(_ *memory, reg *register, instr *instruction) int {
+func xorC(_ *memory, reg *register, instr *instruction) bool {
 	xorReg(reg, reg.C)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func xorD(_ *memory, reg *register, instr *instruction) int {
+func xorD(_ *memory, reg *register, instr *instruction) bool {
 	xorReg(reg, reg.D)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func xorE(_ *memory, reg *register, instr *instruction) int {
+func xorE(_ *memory, reg *register, instr *instruction) bool {
 	xorReg(reg, reg.E)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func xorH(_ *memory, reg *register, instr *instruction) int {
+func xorH(_ *memory, reg *register, instr *instruction) bool {
 	xorReg(reg, reg.H)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func xorL(_ *memory, reg *register, instr *instruction) int {
+func xorL(_ *memory, reg *register, instr *instruction) bool {
 	xorReg(reg, reg.L)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func xorHl(mem *memory, reg *register, instr *instruction) int {
+func xorHl(mem *memory, reg *register, instr *instruction) bool {
 	xorReg(reg, mem.read8(reg.readDuo(REG_HL)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func xorA(_ *memory, reg *register, instr *instruction) int {
+func xorA(_ *memory, reg *register, instr *instruction) bool {
 	xorReg(reg, reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
 func xorReg(reg *register, val uint8) {
@@ -1625,307 +1622,290 @@ func xorReg(reg *register, val uint8) {
 	reg.setC(false)
 }
 
-func rst00(mem *memory, reg *register, instr *instruction) int {
+func rst00(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth++
 	pushStack16(mem, reg, reg.PC+1)
 	reg.PC = 0x00
-	return instr.durationAction
+	return true
 }
 
-func rst10(mem *memory, reg *register, instr *instruction) int {
+func rst10(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth++
 	pushStack16(mem, reg, reg.PC+1)
 	reg.PC = 0x10
-	return instr.durationAction
+	return true
 }
 
-func rst20(mem *memory, reg *register, instr *instruction) int {
+func rst20(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth++
 	pushStack16(mem, reg, reg.PC+1)
 	reg.PC = 0x20
-	return instr.durationAction
+	return true
+}
+
+func rst30(mem *memory, reg *register, instr *instruction) bool {
+	mem.depth++
+	pushStack16(mem, reg, reg.PC+1)
+	reg.PC = 0x30
+	return true
+}
+
+func rst38(mem *memory, reg *register, instr *instruction) bool {
+	mem.depth++
+	pushStack16(mem, reg, reg.PC+1)
+	reg.PC = 0x38
+	return true
 }
 
-func rst08(mem *memory, reg *register, instr *instruction) int {
+func rst08(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth++
 	pushStack16(mem, reg, reg.PC+1)
 	reg.PC = 0x08
-	return instr.durationAction
+	return true
 }
 
-func rst18(mem *memory, reg *register, instr *instruction) int {
+func rst18(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth++
 	pushStack16(mem, reg, reg.PC+1)
 	reg.PC = 0x18
-	return instr.durationAction
+	return true
 }
 
-func rst28(mem *memory, reg *register, instr *instruction) int {
+func rst28(mem *memory, reg *register, instr *instruction) bool {
 	mem.depth++
 	pushStack16(mem, reg, reg.PC+1)
 	reg.PC = 0x28
-	return instr.durationAction
+	return true
 }
 
-func addHLDE(_ *memory, reg *register, instr *instruction) int {
-	val := reg.readDuo(REG_HL)
-	toAdd := reg.readDuo(REG_DE)
-
-	var result uint32 = uint32(val) + uint32(toAdd)
-
-	reg.setN(false)
-	reg.setH(result&0xFFF < uint32(val&0xFFF))
-	reg.setC(result >= 0xFFFF)
-
-	reg.writeDuo(REG_HL, val+toAdd)
-
+func addHLDE(_ *memory, reg *register, instr *instruction) bool {
+	addHLRegister(reg, reg.readDuo(REG_DE))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addHLHL(_ *memory, reg *register, instr *instruction) int {
-	val := reg.readDuo(REG_HL)
-	toAdd := reg.readDuo(REG_HL)
-
-	//reg.setN(false)
-	reg.setH(val&0xf+toAdd&0xf > 0xf)
-	reg.setC(val < 0x100 && val+toAdd > 0xff)
-
-	reg.writeDuo(REG_HL, val+toAdd)
-
+func addHLHL(_ *memory, reg *register, instr *instruction) bool {
+	addHLRegister(reg, reg.readDuo(REG_HL))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addHLSP(_ *memory, reg *register, instr *instruction) int {
-	val := reg.readDuo(REG_HL)
-	toAdd := reg.SP
-
-	reg.setN(false)
-	reg.setH(val&0xf+toAdd&0xf > 0xf)
-	reg.setC(val < 0x100 && val+toAdd > 0xff)
-
-	reg.writeDuo(REG_HL, val+toAdd)
-
+func addHLSP(_ *memory, reg *register, instr *instruction) bool {
+	addHLRegister(reg, reg.SP)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldEHL(mem *memory, reg *register, instr *instruction) int {
+func ldEHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.E = mem.read8(reg.readDuo(REG_HL))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldDHL(mem *memory, reg *register, instr *instruction) int {
+func ldDHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.D = mem.read8(reg.readDuo(REG_HL))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHHL(mem *memory, reg *register, instr *instruction) int {
+func ldHHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.H = mem.read8(reg.readDuo(REG_HL))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLHL(mem *memory, reg *register, instr *instruction) int {
+func ldLHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.L = mem.read8(reg.readDuo(REG_HL))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func jphl(_ *memory, reg *register, instr *instruction) int {
+func jphl(_ *memory, reg *register, instr *instruction) bool {
 	reg.PC = reg.readDuo(REG_HL)
-	return instr.durationAction
+	return true
 }
 
-func ldDEA(mem *memory, reg *register, instr *instruction) int {
+func ldDEA(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_DE), reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldAHL(mem *memory, reg *register, instr *instruction) int {
+func ldAHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = mem.read8(reg.readDuo(REG_HL))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func incL(_ *memory, reg *register, instr *instruction) int {
+func incL(_ *memory, reg *register, instr *instruction) bool {
 	incRegister(&reg.L, reg)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func addHlBc(_ *memory, reg *register, instr *instruction) int {
-	val := reg.readDuo(REG_HL)
-	reg.writeDuo(REG_HL, val+reg.readDuo(REG_BC))
-	reg.setN(false)
-	reg.setH((val&0xf)+(reg.readDuo(REG_BC)&0xf) > 0xf)
-	reg.setC(val+reg.readDuo(REG_BC) > 0xff)
-
+func addHlBc(_ *memory, reg *register, instr *instruction) bool {
+	addHLRegister(reg, reg.readDuo(REG_BC))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldCHL(mem *memory, reg *register, instr *instruction) int {
+func ldCHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.C = mem.read8(reg.readDuo(REG_HL))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBHL(mem *memory, reg *register, instr *instruction) int {
+func ldBHL(mem *memory, reg *register, instr *instruction) bool {
 	reg.B = mem.read8(reg.readDuo(REG_HL))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLB(_ *memory, reg *register, instr *instruction) int {
+func ldLB(_ *memory, reg *register, instr *instruction) bool {
 	reg.L = reg.B
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLC(_ *memory, reg *register, instr *instruction) int {
+func ldLC(_ *memory, reg *register, instr *instruction) bool {
 	reg.L = reg.C
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLD(_ *memory, reg *register, instr *instruction) int {
+func ldLD(_ *memory, reg *register, instr *instruction) bool {
 	reg.L = reg.D
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLE(_ *memory, reg *register, instr *instruction) int {
+func ldLE(_ *memory, reg *register, instr *instruction) bool {
 	reg.L = reg.E
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLH(_ *memory, reg *register, instr *instruction) int {
+func ldLH(_ *memory, reg *register, instr *instruction) bool {
 	reg.L = reg.H
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLL(_ *memory, reg *register, instr *instruction) int {
+func ldLL(_ *memory, reg *register, instr *instruction) bool {
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHB(_ *memory, reg *register, instr *instruction) int {
+func ldHB(_ *memory, reg *register, instr *instruction) bool {
 	reg.H = reg.B
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHD(_ *memory, reg *register, instr *instruction) int {
+func ldHD(_ *memory, reg *register, instr *instruction) bool {
 	reg.H = reg.D
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHE(_ *memory, reg *register, instr *instruction) int {
+func ldHE(_ *memory, reg *register, instr *instruction) bool {
 	reg.H = reg.E
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHH(_ *memory, reg *register, instr *instruction) int {
+func ldHH(_ *memory, reg *register, instr *instruction) bool {
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldHL(_ *memory, reg *register, instr *instruction) int {
+func ldHL(_ *memory, reg *register, instr *instruction) bool {
 	reg.H = reg.L
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldLA(_ *memory, reg *register, instr *instruction) int {
+func ldLA(_ *memory, reg *register, instr *instruction) bool {
 	reg.L = reg.A
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldABC(mem *memory, reg *register, instr *instruction) int {
+func ldABC(mem *memory, reg *register, instr *instruction) bool {
 	reg.A = mem.read8(reg.readDuo(REG_BC))
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAB(_ *memory, reg *register, instr *instruction) int {
+func adcAB(_ *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, reg.B)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAC(_ *memory, reg *register, instr *instruction) int {
+func adcAC(_ *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, reg.C)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAD(_ *memory, reg *register, instr *instruction) int {
+func adcAD(_ *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, reg.D)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAE(_ *memory, reg *register, instr *instruction) int {
+func adcAE(_ *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, reg.E)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAH(_ *memory, reg *register, instr *instruction) int {
+func adcAH(_ *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, reg.H)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAL(_ *memory, reg *register, instr *instruction) int {
+func adcAL(_ *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, reg.L)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAHL(mem *memory, reg *register, instr *instruction) int {
+func adcAHL(mem *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, mem.read8(reg.readDuo(REG_HL)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAA(_ *memory, reg *register, instr *instruction) int {
+func adcAA(_ *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func adcAd8(mem *memory, reg *register, instr *instruction) int {
+func adcAd8(mem *memory, reg *register, instr *instruction) bool {
 	addCarry(reg, &reg.A, readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
 func addCarry(reg *register, val *uint8, toAdd uint8) {
@@ -1940,7 +1920,7 @@ func addCarry(reg *register, val *uint8, toAdd uint8) {
 		hc++
 	}
 
-	reg.setZ(i == 0)
+	reg.setZ(uint8(i) == 0)
 	reg.setN(false)
 	reg.setH(hc > 0xf)
 	reg.setC(i > 0xff)
@@ -1948,12 +1928,17 @@ func addCarry(reg *register, val *uint8, toAdd uint8) {
 	*val = uint8(i)
 }
 
-func halt(_ *memory, reg *register, instr *instruction) int {
-	reg.incPC(instr.bytes + 1)
-	return instr.durationAction
+func halt(mem *memory, reg *register, instr *instruction) bool {
+	if !reg.interruptMaster && mem.interruptPending() {
+		reg.haltBug = true
+	} else {
+		reg.halted = true
+	}
+	reg.incPC(instr.bytes)
+	return true
 }
 
-func rrA(_ *memory, reg *register, instr *instruction) int {
+func rra(_ *memory, reg *register, instr *instruction) bool {
 	val := reg.A
 	carry := reg.isC()
 
@@ -1962,189 +1947,203 @@ func rrA(_ *memory, reg *register, instr *instruction) int {
 		reg.A = setBit(reg.A, 7)
 	}
 
-	reg.setZ(reg.A == 0)
+	reg.setZ(false)
 	reg.setN(false)
 	reg.setH(false)
 	reg.setC(val&0x1 == 1)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func rrca(_ *memory, reg *register, instr *instruction) int {
-	val := reg.A
-	reg.A >>= 1
+func rrca(_ *memory, reg *register, instr *instruction) bool {
+	var carry bool
+	reg.A, carry = rRight(reg.A)
 
-	reg.setZ(reg.A == 0)
+	reg.setZ(false)
 	reg.setN(false)
 	reg.setH(false)
-	reg.setC(val&0x1 == 1)
+	reg.setC(carry)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func lda16Sp(mem *memory, reg *register, instr *instruction) int {
+func lda16Sp(mem *memory, reg *register, instr *instruction) bool {
 	mem.write16(readArgHalfword(mem, reg), reg.SP)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldSPHl(_ *memory, reg *register, instr *instruction) int {
-	fmt.Printf("Loading into SP (HL): %#04x\n", reg.readDuo(REG_HL))
+func ldSPHl(_ *memory, reg *register, instr *instruction) bool {
 	reg.SP = reg.readDuo(REG_HL)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func daa(_ *memory, reg *register, instr *instruction) int {
-	val := uint16(reg.A)
+func daa(_ *memory, reg *register, instr *instruction) bool {
 	if reg.isN() {
-		if reg.isH() {
-			val = (val - 0x06) & 0xFF
-		}
 		if reg.isC() {
-			val -= 0x60
+			reg.A -= 0x60
+		}
+		if reg.isH() {
+			reg.A -= 0x06
 		}
 	} else {
-		if reg.isH() || (val&0xf) > 9 {
-			val += 0x06
+		if reg.isC() || reg.A > 0x99 {
+			reg.A += 0x60
+			reg.setC(true)
 		}
-		if reg.isC() || val > 0x9f {
-			val += 0x60
+		if reg.isH() || reg.A&0xf > 0x9 {
+			reg.A += 0x06
 		}
 	}
 
-	reg.A = uint8(val)
-	reg.setH(false)
 	reg.setZ(reg.A == 0)
-
-	if val > 0xff {
-		reg.setC(true)
-	}
+	reg.setH(false)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldBCA(mem *memory, reg *register, instr *instruction) int {
+func ldBCA(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(reg.readDuo(REG_BC), reg.A)
 
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ldACA(mem *memory, reg *register, instr *instruction) int {
+func ldACA(mem *memory, reg *register, instr *instruction) bool {
 	mem.write8(0xFF00+uint16(reg.C), reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func stop(_ *memory, reg *register, instr *instruction) int {
+func ldAC8(mem *memory, reg *register, instr *instruction) bool {
+	reg.A = mem.read8(0xFF00 + uint16(reg.C))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func scf(_ *memory, reg *register, instr *instruction) int {
+func addSPr8(mem *memory, reg *register, instr *instruction) bool {
+	reg.SP = addSPSigned(reg, readArgByte(mem, reg))
+	reg.incPC(instr.bytes)
+	return true
+}
+
+/*
+The opcodes without an instruction lock up the CPU until it is powered off. The program counter
+is not advanced, and the Gameboy stops executing instructions and servicing interrupts.
+*/
+func illegal(_ *memory, reg *register, instr *instruction) bool {
+	reg.locked = true
+	return true
+}
+
+func stop(_ *memory, reg *register, instr *instruction) bool {
+	reg.stopped = true
+	reg.incPC(instr.bytes)
+	return true
+}
+
+func scf(_ *memory, reg *register, instr *instruction) bool {
 	reg.setN(false)
 	reg.setH(false)
 	reg.setC(true)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func decSP(_ *memory, reg *register, instr *instruction) int {
+func incSP(_ *memory, reg *register, instr *instruction) bool {
+	reg.SP += 1
+	reg.incPC(instr.bytes)
+	return true
+}
+
+func decSP(_ *memory, reg *register, instr *instruction) bool {
 	reg.SP -= 1
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func ccf(_ *memory, reg *register, instr *instruction) int {
+func ccf(_ *memory, reg *register, instr *instruction) bool {
 	reg.setN(false)
 	reg.setH(false)
 	reg.setC(!reg.isC())
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAB(_ *memory, reg *register, instr *instruction) int {
+func sbcAB(_ *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, reg.B)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAC(_ *memory, reg *register, instr *instruction) int {
+func sbcAC(_ *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, reg.C)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAD(_ *memory, reg *register, instr *instruction) int {
+func sbcAD(_ *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, reg.D)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAE(_ *memory, reg *register, instr *instruction) int {
+func sbcAE(_ *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, reg.E)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAH(_ *memory, reg *register, instr *instruction) int {
+func sbcAH(_ *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, reg.H)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAL(_ *memory, reg *register, instr *instruction) int {
+func sbcAL(_ *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, reg.L)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAHL(mem *memory, reg *register, instr *instruction) int {
+func sbcAHL(mem *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, mem.read8(reg.readDuo(REG_HL)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAA(_ *memory, reg *register, instr *instruction) int {
+func sbcAA(_ *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, reg.A)
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
-func sbcAd8(mem *memory, reg *register, instr *instruction) int {
+func sbcAd8(mem *memory, reg *register, instr *instruction) bool {
 	substractWithCarry(reg, readArgByte(mem, reg))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
 
 func substractWithCarry(reg *register, val uint8) {
-	if reg.isC() {
-		val++
-	}
+	carry := btoi(reg.isC())
+	result := int(reg.A) - int(val) - carry
 
-	result := reg.A - val
-
-	reg.setZ(reg.A == 0)
+	reg.setZ(uint8(result) == 0)
 	reg.setN(true)
-	reg.setH(reg.A&0xf-val&0xf > 0xf)
-	reg.setC(int16(reg.A)-int16(val) < 0)
+	reg.setH(int(reg.A&0xf)-int(val&0xf)-carry < 0)
+	reg.setC(result < 0)
 
-	reg.A = result
+	reg.A = uint8(result)
 }
 
-func ldHLSPr8(mem *memory, reg *register, instr *instruction) int {
-	r8 := int16(int8(readArgByte(mem, reg)))
-
-	reg.writeDuo(REG_HL, uint16(int16(reg.SP)+r8))
-
-	reg.setZ(false)
-	reg.setN(false)
+func ldHLSPr8(mem *memory, reg *register, instr *instruction) bool {
+	reg.writeDuo(REG_HL, addSPSigned(reg, readArgByte(mem, reg)))
 	reg.incPC(instr.bytes)
-	return instr.durationAction
+	return true
 }
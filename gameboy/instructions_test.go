@@ -1,11 +1,13 @@
 package gameboy
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func dummyMemory() *memory {
-	bootrom := [256]uint8{}
 	cartridge := [32 * 1024]uint8{}
-	return memInit(bootrom[:], cartridge[:])
+	return memInit(cartridge[:], new(cartridgeInfo))
 }
 
 func dummyRegs() *register {
@@ -15,18 +17,18 @@ func dummyRegs() *register {
 func TestIncB(t *testing.T) {
 	mem := dummyMemory()
 	regs := dummyRegs()
-	regs.B = byteRegister(0x0)
+	regs.B = 0x0
 
 	result := dummyRegs()
-	result.B = byteRegister(0x1)
-	result.Flag.Z = false
-	result.Flag.N = false
+	result.B = 0x1
+	result.setZ(false)
+	result.setN(false)
 
 	testInstruction(t, mem, regs, incB, result, mem, "INC B")
 
-	regs.B = byteRegister(0xf)
-	result.B = byteRegister(0x10)
-	result.Flag.H = true
+	regs.B = 0xf
+	result.B = 0x10
+	result.setH(true)
 
 	testInstruction(t, mem, regs, incB, result, mem, "INC B")
 }
@@ -34,18 +36,18 @@ func TestIncB(t *testing.T) {
 func TestIncC(t *testing.T) {
 	mem := dummyMemory()
 	regs := dummyRegs()
-	regs.C = byteRegister(0x0)
+	regs.C = 0x0
 
 	result := dummyRegs()
-	result.C = byteRegister(0x1)
-	result.Flag.Z = false
-	result.Flag.N = false
+	result.C = 0x1
+	result.setZ(false)
+	result.setN(false)
 
 	testInstruction(t, mem, regs, incC, result, mem, "INC C")
 
-	regs.C = byteRegister(0xf)
-	result.C = byteRegister(0x10)
-	result.Flag.H = true
+	regs.C = 0xf
+	result.C = 0x10
+	result.setH(true)
 
 	testInstruction(t, mem, regs, incC, result, mem, "INC C")
 }
@@ -53,20 +55,19 @@ func TestIncC(t *testing.T) {
 func TestDecB(t *testing.T) {
 	mem := dummyMemory()
 	regs := dummyRegs()
-	regs.B = byteRegister(0x1)
+	regs.B = 0x1
 
 	result := dummyRegs()
-	result.B = byteRegister(0x0)
-	result.Flag.Z = true
-	result.Flag.N = true
-	result.Flag.H = true
+	result.B = 0x0
+	result.setZ(true)
+	result.setN(true)
 
 	testInstruction(t, mem, regs, decB, result, mem, "DEC B")
 
-	regs.B = byteRegister(0x10)
-	result.B = byteRegister(0xf)
-	result.Flag.Z = false
-	result.Flag.H = false
+	regs.B = 0x10
+	result.B = 0xf
+	result.setZ(false)
+	result.setH(true)
 
 	testInstruction(t, mem, regs, decB, result, mem, "DEC B")
 }
@@ -74,31 +75,32 @@ func TestDecB(t *testing.T) {
 func TestDecC(t *testing.T) {
 	mem := dummyMemory()
 	regs := dummyRegs()
-	regs.C = byteRegister(0x1)
+	regs.C = 0x1
 
 	result := dummyRegs()
-	result.C = byteRegister(0x0)
-	result.Flag.Z = true
-	result.Flag.N = true
-	result.Flag.H = true
+	result.C = 0x0
+	result.setZ(true)
+	result.setN(true)
 
 	testInstruction(t, mem, regs, decC, result, mem,"DEC C")
 
-	regs.C = byteRegister(0x10)
-	result.C = byteRegister(0xf)
-	result.Flag.Z = false
-	result.Flag.H = false
+	regs.C = 0x10
+	result.C = 0xf
+	result.setZ(false)
+	result.setH(true)
 
 	testInstruction(t, mem, regs, decC, result, mem,"DEC C")
 }
 
 func TestLDSP(t *testing.T) {
 	regs := dummyRegs()
+	regs.PC = 0xc000
 	mem := dummyMemory()
-	mem.write16(0x1, 0xfefe)
+	mem.write16(0xc001, 0xfefe)
 
 	resultReg := dummyRegs()
-	resultReg.SP = halfWordRegister(0xfefe)
+	resultReg.PC = 0xc000
+	resultReg.SP = 0xfefe
 
 	testInstruction(t, mem, regs, ldSPd16, resultReg, mem, "LD SP")
 }
@@ -107,44 +109,46 @@ func TestXOR(t *testing.T) {
 	regs := dummyRegs()
 	mem := dummyMemory()
 
-	regs.A = byteRegister(0xfe)
+	regs.A = 0xfe
 
 	resultRegs := dummyRegs()
 	resultRegs.A = 0
-	resultRegs.Flag.Z = true
-	resultRegs.Flag.N = false
-	resultRegs.Flag.H = false
-	resultRegs.Flag.C = false
+	resultRegs.setZ(true)
+	resultRegs.setN(false)
+	resultRegs.setH(false)
+	resultRegs.setC(false)
 
 	testInstruction(t, mem, regs, xorA, resultRegs, mem, "XOR A")
 }
 
 func TestLDHL(t *testing.T) {
 	regs := dummyRegs()
+	regs.PC = 0xc000
 	mem := dummyMemory()
-	mem.write16(0x1, 0xfefe)
+	mem.write16(0xc001, 0xfefe)
 
 	resultReg := dummyRegs()
+	resultReg.PC = 0xc000
 	resultReg.writeDuo(REG_HL, 0xfefe)
 
-	testInstruction(t, mem, regs, ldHL, resultReg, mem, "LD HL")
+	testInstruction(t, mem, regs, ldHLd16, resultReg, mem, "LD HL")
 }
 
 func TestLDDHLA(t *testing.T) {
 	regs := dummyRegs()
 	mem := dummyMemory()
 
-	regs.writeDuo(REG_HL, 0x1000)
+	regs.writeDuo(REG_HL, 0xc100)
 	regs.A = 0xfe
 
 	resultMem := dummyMemory()
-	resultMem.write8(0x1000, 0xfe)
+	resultMem.write8(0xc100, 0xfe)
 
 	resultReg := dummyRegs()
-	resultReg.A = byteRegister(0xfe)
-	resultReg.writeDuo(REG_HL, 0xfff)
+	resultReg.A = 0xfe
+	resultReg.writeDuo(REG_HL, 0xc0ff)
 
-	testInstruction(t, mem, regs, lddHLA, resultReg, mem, "LD (HL-) A")
+	testInstruction(t, mem, regs, lddHLA, resultReg, resultMem, "LD (HL-) A")
 }
 
 func TestBit7H(t *testing.T) {
@@ -155,28 +159,119 @@ func TestBit7H(t *testing.T) {
 
 	resultReg := dummyRegs()
 	resultReg.H = 0xf0
-	resultReg.Flag.Z = false
-	resultReg.Flag.N = false
-	resultReg.Flag.H = true
+	resultReg.setZ(false)
+	resultReg.setN(false)
+	resultReg.setH(true)
 
 	testCbInstruction(t, mem, regs, bit_7_h, resultReg, mem, "BIT 7,H")
 }
 
 func TestJRNZ(t *testing.T) {
 	regs := dummyRegs()
+	regs.PC = 0xc000
 	mem := dummyMemory()
 
-	mem.write16(0x1, 0xffee)
+	mem.write8(0xc001, 0xee)
 
 	resultRegs := dummyRegs()
-	resultRegs.PC = 0xffee
+	resultRegs.PC = 0xbfee
 	testInstruction(t, mem, regs, jrNZr8, resultRegs, mem, "JR NZ")
 
-	mem.write16(0x1, 0xffee)
-	regs.Flag.Z = true
+	regs.PC = 0xc000
+	regs.setZ(true)
 	testInstruction(t, mem, regs, jrNZr8, regs, mem, "JR NZ")
 }
 
+func TestAddSPr8(t *testing.T) {
+	regs := dummyRegs()
+	regs.PC = 0xc000
+	regs.SP = 0xfff8
+	mem := dummyMemory()
+	mem.write8(0xc001, 0x08)
+
+	resultRegs := dummyRegs()
+	resultRegs.PC = 0xc000
+	resultRegs.SP = 0x0000
+	resultRegs.setH(true)
+	resultRegs.setC(true)
+
+	testInstruction(t, mem, regs, addSPr8, resultRegs, mem, "ADD SP,r8")
+}
+
+func TestPushPopStack(t *testing.T) {
+	regs := dummyRegs()
+	regs.SP = 0xfffe
+	mem := dummyMemory()
+
+	pushStack16(mem, regs, 0x1234)
+	if regs.SP != 0xfffc || mem.read8(0xfffd) != 0x12 || mem.read8(0xfffc) != 0x34 {
+		t.Errorf("Push stored %#02x %#02x with SP %#04x", mem.read8(0xfffd), mem.read8(0xfffc), regs.SP)
+	}
+	if val := popStack16(mem, regs); val != 0x1234 || regs.SP != 0xfffe {
+		t.Errorf("Pop returned %#04x with SP %#04x", val, regs.SP)
+	}
+}
+
+func TestInstructionTableComplete(t *testing.T) {
+	instructions := createInstructionTable()
+	for code := 0; code < 256; code++ {
+		if instructions[code] == nil {
+			t.Errorf("Instruction %#02x is missing", code)
+		}
+	}
+}
+
+func TestRrC(t *testing.T) {
+	regs := dummyRegs()
+	mem := dummyMemory()
+
+	regs.C = 0x01
+	regs.setC(true)
+
+	resultRegs := dummyRegs()
+	resultRegs.C = 0x80
+	resultRegs.setC(true)
+
+	testCbInstruction(t, mem, regs, rrC, resultRegs, mem, "RR C")
+}
+
+func TestSraA(t *testing.T) {
+	regs := dummyRegs()
+	mem := dummyMemory()
+
+	regs.A = 0x81
+
+	resultRegs := dummyRegs()
+	resultRegs.A = 0xc0
+	resultRegs.setC(true)
+
+	testCbInstruction(t, mem, regs, sraA, resultRegs, mem, "SRA A")
+}
+
+func TestSwapHL(t *testing.T) {
+	regs := dummyRegs()
+	mem := dummyMemory()
+
+	regs.writeDuo(REG_HL, 0xc100)
+	mem.write8(0xc100, 0x1f)
+
+	resultRegs := dummyRegs()
+	resultRegs.writeDuo(REG_HL, 0xc100)
+	resultMem := dummyMemory()
+	resultMem.write8(0xc100, 0xf1)
+
+	testCbInstruction(t, mem, regs, swapHL, resultRegs, resultMem, "SWAP (HL)")
+}
+
+func TestCBInstructionTableComplete(t *testing.T) {
+	cbInstructions := createCBInstructionTable()
+	for code := 0; code < 256; code++ {
+		if cbInstructions[code] == nil {
+			t.Errorf("CB instruction %#02x is missing", code)
+		}
+	}
+}
+
 func testCbInstruction(t *testing.T, mem *memory, regs *register, executor cbInstructionExecutor, resultReg *register, resultMem *memory, name string) {
 	executor(mem, regs, new(cbInstruction))
 
@@ -184,7 +279,7 @@ func testCbInstruction(t *testing.T, mem *memory, regs *register, executor cbIns
 		t.Errorf("Instruction %s failed, registers does not match:\nExpected:\n%+v\n\nGot:\n%+v", name, resultReg, regs)
 	}
 
-	if *mem != *resultMem {
+	if !reflect.DeepEqual(mem, resultMem) {
 		t.Errorf("Instruction %s failed, memory does not match:\nExpected:\n%+v\n\nGot:\n%+v", name, resultMem, mem)
 	}
 }
@@ -197,7 +292,7 @@ func testInstruction(t *testing.T, mem *memory, regs *register, executor instruc
 		t.Errorf("Instruction %s failed, registers does not match:\nExpected:\n%+v\n\nGot:\n%+v", name, resultReg, regs)
 	}
 
-	if *mem != *resultMem {
+	if !reflect.DeepEqual(mem, resultMem) {
 		t.Errorf("Instruction %s failed, memory does not match:\nExpected:\n%+v\n\nGot:\n%+v", name, resultMem, mem)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,185 @@
+package gameboy
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+A memory bank controller maps the ROM and RAM banks of the cartridge into the ROM window at
+0x0000-0x7FFF and the external RAM window at 0xA000-0xBFFF. Writes to the ROM window go to the
+registers of the controller.
+*/
+type mbc interface {
+	readRom(address uint16) uint8
+	writeRom(address uint16, val uint8)
+	readRam(address uint16) uint8
+	writeRam(address uint16, val uint8)
+	// Advances the hardware on the cartridge, like a clock
+	tick(cycles int)
+	// Lists the registers of the controller for the save state
+	state(s *state)
+	// Describes the selected banks for the debugger
+	String() string
+}
+
+// The ROM and RAM banks of the cartridge, which are shared by all memory bank controllers
+type banks struct {
+	rom [][16 * 1024]uint8
+	ram [][8 * 1024]uint8
+	// The size of the RAM in bytes, RAM smaller than a bank is mirrored across the bank
+	ramSize int
+}
+
+// Most controllers have no hardware that runs by itself
+func (banks *banks) tick(cycles int) {
+}
+
+func newBanks(memory *memory, ramSize int) banks {
+	return banks{
+		rom:     memory.switchableRomBank,
+		ram:     memory.switchableRamBank,
+		ramSize: ramSize,
+	}
+}
+
+// Banks beyond the size of the ROM mirror the lower banks, because the upper bank bits are not connected
+func (banks *banks) readRomBank(bank int, address uint16) uint8 {
+	return banks.rom[bank%len(banks.rom)][address&0x3FFF]
+}
+
+func (banks *banks) ramOffset(address uint16) int {
+	offset := int(address - 0xA000)
+	if banks.ramSize < len(banks.ram[0]) {
+		offset %= banks.ramSize
+	}
+	return offset
+}
+
+// Cartridges without RAM do not drive the bus
+func (banks *banks) readRamBank(bank int, address uint16) uint8 {
+	if len(banks.ram) == 0 {
+		return 0xFF
+	}
+	return banks.ram[bank%len(banks.ram)][banks.ramOffset(address)]
+}
+
+func (banks *banks) writeRamBank(bank int, address uint16, val uint8) {
+	if len(banks.ram) == 0 {
+		return
+	}
+	banks.ram[bank%len(banks.ram)][banks.ramOffset(address)] = val
+}
+
+func newMBC(cartInfo *cartridgeInfo, memory *memory) mbc {
+	banks := newBanks(memory, cartInfo.ramBytes())
+	switch {
+	case cartInfo.mbc1:
+		return &mbc1{banks: banks, bank1: 1}
+	case cartInfo.mbc2:
+		return &mbc2{banks: banks, romBank: 1}
+	case cartInfo.mbc3:
+		mbc := &mbc3{banks: banks, romBank: 1}
+		if cartInfo.timer {
+			mbc.rtc = &rtc{}
+		}
+		return mbc
+	case cartInfo.mbc5:
+		mbc := &mbc5{banks: banks, romBank: 1}
+		if cartInfo.rumble {
+			mbc.rumble = memory.setRumble
+		}
+		return mbc
+	case cartInfo.CartType == rom_only || cartInfo.CartType == rom_ram || cartInfo.CartType == rom_ram_battery:
+		return &romOnly{banks: banks}
+	default:
+		return &unsupportedMBC{banks: banks, name: cartInfo.cartridgeTypeCodeString(), raise: memory.raise}
+	}
+}
+
+// Returns the real-time clock of the cartridge, nil when it has none
+func (memory *memory) cartridgeClock() *rtc {
+	if mbc, ok := memory.mbc.(*mbc3); ok {
+		return mbc.rtc
+	}
+	return nil
+}
+
+// Lets the real-time clock of the cartridge follow the time of the host from now on
+func (memory *memory) useHostClock() {
+	if rtc := memory.cartridgeClock(); rtc != nil {
+		rtc.synced = time.Now()
+	}
+}
+
+// Passes changes of the rumble motor on to the host
+func (memory *memory) setRumble(on bool) {
+	if memory.motor != nil && on != memory.motor.on {
+		memory.motor.on = on
+		memory.motor.rumble(on)
+	}
+}
+
+// Cartridges without a controller have two fixed ROM banks and RAM that is always enabled
+type romOnly struct {
+	banks
+}
+
+func (rom *romOnly) readRom(address uint16) uint8 {
+	if address < 0x4000 {
+		return rom.readRomBank(0, address)
+	}
+	return rom.readRomBank(1, address)
+}
+
+// Some games write to the ROM even though there is no controller, which does nothing
+func (rom *romOnly) writeRom(address uint16, val uint8) {
+}
+
+func (rom *romOnly) readRam(address uint16) uint8 {
+	return rom.readRamBank(0, address)
+}
+
+func (rom *romOnly) writeRam(address uint16, val uint8) {
+	rom.writeRamBank(0, address, val)
+}
+
+func (rom *romOnly) state(s *state) {
+}
+
+func (rom *romOnly) String() string {
+	return "No MBC"
+}
+
+// A controller that is not emulated. The first two ROM banks can be read, bank switching raises a fault.
+type unsupportedMBC struct {
+	banks
+	name  string
+	raise func(address uint16, reason string)
+}
+
+func (mbc *unsupportedMBC) readRom(address uint16) uint8 {
+	if address < 0x4000 {
+		return mbc.readRomBank(0, address)
+	}
+	return mbc.readRomBank(1, address)
+}
+
+func (mbc *unsupportedMBC) writeRom(address uint16, val uint8) {
+	mbc.raise(address, fmt.Sprintf("%s banking not implemented", mbc.name))
+}
+
+// The RAM can not be enabled
+func (mbc *unsupportedMBC) readRam(address uint16) uint8 {
+	return 0xFF
+}
+
+func (mbc *unsupportedMBC) writeRam(address uint16, val uint8) {
+}
+
+func (mbc *unsupportedMBC) state(s *state) {
+}
+
+func (mbc *unsupportedMBC) String() string {
+	return mbc.name + " (not supported)"
+}
@@ -0,0 +1,77 @@
+package gameboy
+
+import "fmt"
+
+/*
+MBC1 supports up to 2 MB of ROM and 32 kB of RAM. The ROM bank is made of two registers: BANK1 holds
+the lower 5 bits and BANK2 the upper 2 bits. In mode 1 BANK2 also selects the bank at 0x0000 and the
+RAM bank, which is used by cartridges with 32 kB of RAM. Cartridges with 512 kB of ROM or less do not
+connect the upper bits, so the selected bank is mirrored.
+*/
+type mbc1 struct {
+	banks
+	ramEnabled bool
+	bank1      uint8
+	bank2      uint8
+	mode       uint8
+}
+
+func (mbc *mbc1) readRom(address uint16) uint8 {
+	if address < 0x4000 {
+		if mbc.mode == 1 {
+			return mbc.readRomBank(int(mbc.bank2)<<5, address)
+		}
+		return mbc.readRomBank(0, address)
+	}
+	return mbc.readRomBank(int(mbc.bank2)<<5|int(mbc.bank1), address)
+}
+
+func (mbc *mbc1) writeRom(address uint16, val uint8) {
+	switch {
+	case address < 0x2000:
+		mbc.ramEnabled = val&0xF == 0xA
+	case address < 0x4000:
+		// BANK1 can not be 0, which is why banks 0x20, 0x40 and 0x60 can only be mapped at 0x0000
+		mbc.bank1 = val & 0x1F
+		if mbc.bank1 == 0 {
+			mbc.bank1 = 1
+		}
+	case address < 0x6000:
+		mbc.bank2 = val & 0x3
+	default:
+		mbc.mode = val & 0x1
+	}
+}
+
+func (mbc *mbc1) ramBank() int {
+	if mbc.mode == 1 {
+		return int(mbc.bank2)
+	}
+	return 0
+}
+
+// Disabled RAM does not drive the bus
+func (mbc *mbc1) readRam(address uint16) uint8 {
+	if !mbc.ramEnabled {
+		return 0xFF
+	}
+	return mbc.readRamBank(mbc.ramBank(), address)
+}
+
+func (mbc *mbc1) writeRam(address uint16, val uint8) {
+	if mbc.ramEnabled {
+		mbc.writeRamBank(mbc.ramBank(), address, val)
+	}
+}
+
+func (mbc *mbc1) state(s *state) {
+	s.field(&mbc.ramEnabled)
+	s.field(&mbc.bank1)
+	s.field(&mbc.bank2)
+	s.field(&mbc.mode)
+}
+
+func (mbc *mbc1) String() string {
+	return fmt.Sprintf("MBC1 mode %d, ROM bank %d, RAM bank %d, RAM enabled: %t",
+		mbc.mode, int(mbc.bank2)<<5|int(mbc.bank1), mbc.ramBank(), mbc.ramEnabled)
+}
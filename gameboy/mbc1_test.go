@@ -0,0 +1,81 @@
+package gameboy
+
+import "testing"
+
+func TestMBC1RomBanks(t *testing.T) {
+	gb := bankedCart(0x03, 0x05, 0x03, 64)
+
+	if bank := gb.Read(0x4200); bank != 1 {
+		t.Errorf("Expected bank 1 at power on, got %d", bank)
+	}
+	gb.Write(0x2000, 0x00)
+	if bank := gb.Read(0x4200); bank != 1 {
+		t.Errorf("Expected bank 0 to select bank 1, got %d", bank)
+	}
+	gb.Write(0x2000, 0xE5)
+	if bank := gb.Read(0x4200); bank != 5 {
+		t.Errorf("Expected the lower 5 bits to select bank 5, got %d", bank)
+	}
+
+	gb.Write(0x4000, 0x01)
+	if bank := gb.Read(0x4200); bank != 0x25 {
+		t.Errorf("Expected the upper bits to select bank 0x25, got %#02x", bank)
+	}
+	gb.Write(0x2000, 0x00)
+	if bank := gb.Read(0x4200); bank != 0x21 {
+		t.Errorf("Expected bank 0x20 to select bank 0x21, got %#02x", bank)
+	}
+	if bank := gb.Read(0x0200); bank != 0 {
+		t.Errorf("Expected bank 0 at 0x0000 in mode 0, got %d", bank)
+	}
+
+	gb.Write(0x6000, 0x01)
+	if bank := gb.Read(0x0200); bank != 0x20 {
+		t.Errorf("Expected bank 0x20 at 0x0000 in mode 1, got %#02x", bank)
+	}
+}
+
+func TestMBC1MirroredBanks(t *testing.T) {
+	gb := bankedCart(0x03, 0x03, 0x03, 16)
+
+	gb.Write(0x2000, 0x13)
+	if bank := gb.Read(0x4200); bank != 3 {
+		t.Errorf("Expected bank 0x13 to mirror bank 3, got %d", bank)
+	}
+}
+
+func TestMBC1RamEnable(t *testing.T) {
+	gb := bankedCart(0x03, 0x00, 0x03, 2)
+
+	for _, val := range []uint8{0x0A, 0x1A} {
+		gb.Write(0x0000, val)
+		gb.Write(0xA000, val)
+		if gb.Read(0xA000) != val {
+			t.Errorf("Expected %#02x to enable the RAM", val)
+		}
+	}
+
+	gb.Write(0x1FFF, 0x0E)
+	if gb.Read(0xA000) != 0xFF {
+		t.Errorf("Expected 0x0E to disable the RAM")
+	}
+}
+
+func TestMBC1RamBanks(t *testing.T) {
+	gb := bankedCart(0x03, 0x00, 0x03, 2)
+	gb.Write(0x0000, 0x0A)
+
+	gb.Write(0x4000, 0x02)
+	gb.Write(0xA000, 0x22)
+	gb.Write(0x6000, 0x01)
+	gb.Write(0xA000, 0x33)
+
+	gb.Write(0x6000, 0x00)
+	if gb.Read(0xA000) != 0x22 {
+		t.Errorf("Expected RAM bank 0 in mode 0")
+	}
+	gb.Write(0x6000, 0x01)
+	if gb.Read(0xA000) != 0x33 {
+		t.Errorf("Expected RAM bank 2 in mode 1")
+	}
+}
@@ -0,0 +1,58 @@
+package gameboy
+
+import "fmt"
+
+/*
+MBC2 supports up to 256 kB of ROM and has 512 half-bytes of RAM built in. Both registers are in
+0x0000-0x3FFF, bit 8 of the address selects the register: RAM enable when it is 0 and the ROM bank
+when it is 1. The RAM is mirrored across 0xA000-0xBFFF and the upper half of every byte is not
+connected, it reads as 1s.
+*/
+type mbc2 struct {
+	banks
+	ramEnabled bool
+	romBank    uint8
+}
+
+func (mbc *mbc2) readRom(address uint16) uint8 {
+	if address < 0x4000 {
+		return mbc.readRomBank(0, address)
+	}
+	return mbc.readRomBank(int(mbc.romBank), address)
+}
+
+func (mbc *mbc2) writeRom(address uint16, val uint8) {
+	if address >= 0x4000 {
+		return
+	}
+	if address&0x100 == 0 {
+		mbc.ramEnabled = val&0xF == 0xA
+	} else {
+		mbc.romBank = val & 0xF
+		if mbc.romBank == 0 {
+			mbc.romBank = 1
+		}
+	}
+}
+
+func (mbc *mbc2) readRam(address uint16) uint8 {
+	if !mbc.ramEnabled {
+		return 0xFF
+	}
+	return mbc.readRamBank(0, address) | 0xF0
+}
+
+func (mbc *mbc2) writeRam(address uint16, val uint8) {
+	if mbc.ramEnabled {
+		mbc.writeRamBank(0, address, val&0x0F)
+	}
+}
+
+func (mbc *mbc2) state(s *state) {
+	s.field(&mbc.ramEnabled)
+	s.field(&mbc.romBank)
+}
+
+func (mbc *mbc2) String() string {
+	return fmt.Sprintf("MBC2 ROM bank %d, RAM enabled: %t", mbc.romBank, mbc.ramEnabled)
+}
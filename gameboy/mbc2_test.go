@@ -0,0 +1,64 @@
+package gameboy
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMBC2Registers(t *testing.T) {
+	gb := bankedCart(0x06, 0x03, 0x00, 16)
+
+	// Bit 8 of the address selects the register in the whole 0x0000-0x3FFF range
+	gb.Write(0x0100, 0x03)
+	if bank := gb.Read(0x4200); bank != 3 {
+		t.Errorf("Expected bank 3, got %d", bank)
+	}
+	gb.Write(0x3F00, 0x00)
+	if bank := gb.Read(0x4200); bank != 1 {
+		t.Errorf("Expected bank 0 to select bank 1, got %d", bank)
+	}
+
+	gb.Write(0x2000, 0x0A)
+	gb.Write(0xA000, 0x05)
+	if gb.Read(0xA000) != 0xF5 {
+		t.Errorf("Expected a write to 0x2000 to enable the RAM")
+	}
+	if bank := gb.Read(0x4200); bank != 1 {
+		t.Errorf("Expected enabling the RAM to keep bank 1, got %d", bank)
+	}
+	gb.Write(0x0000, 0x00)
+	if gb.Read(0xA000) != 0xFF {
+		t.Errorf("Expected the RAM to be disabled")
+	}
+}
+
+func TestMBC2Ram(t *testing.T) {
+	gb := bankedCart(0x06, 0x03, 0x00, 16)
+	gb.Write(0x0000, 0x0A)
+
+	gb.Write(0xA001, 0xAB)
+	for _, address := range []uint16{0xA001, 0xA201, 0xBE01} {
+		if val := gb.Read(address); val != 0xFB {
+			t.Errorf("Expected the lower half-byte with the upper half set at %#04x, got %#02x", address, val)
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := gb.SaveRAM(buffer); err != nil {
+		t.Fatal(err)
+	}
+	saved := buffer.Bytes()
+	if len(saved) != 512 || saved[1] != 0x0B {
+		t.Fatalf("Expected 512 half-bytes, got %d bytes", len(saved))
+	}
+
+	saved[1] = 0xFC
+	loaded := bankedCart(0x06, 0x03, 0x00, 16)
+	if err := loaded.LoadRAM(bytes.NewReader(saved)); err != nil {
+		t.Fatal(err)
+	}
+	loaded.Write(0x0000, 0x0A)
+	if val := loaded.Read(0xA001); val != 0xFC {
+		t.Errorf("Expected the loaded half-byte, got %#02x", val)
+	}
+}
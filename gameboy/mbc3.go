@@ -0,0 +1,95 @@
+package gameboy
+
+import "fmt"
+
+/*
+MBC3 supports up to 2 MB of ROM with a 7-bit ROM bank and 32 kB of RAM in 4 banks. Cartridges with a
+timer have a real-time clock, whose registers are selected with the RAM bank register.
+*/
+type mbc3 struct {
+	banks
+	// Enables the RAM and the clock registers
+	ramEnabled bool
+	romBank    uint8
+	// A RAM bank from 0x00 to 0x03, or a clock register from 0x08 to 0x0C
+	ramBank uint8
+	// nil when the cartridge has no timer
+	rtc *rtc
+}
+
+func (mbc *mbc3) readRom(address uint16) uint8 {
+	if address < 0x4000 {
+		return mbc.readRomBank(0, address)
+	}
+	return mbc.readRomBank(int(mbc.romBank), address)
+}
+
+func (mbc *mbc3) writeRom(address uint16, val uint8) {
+	switch {
+	case address < 0x2000:
+		mbc.ramEnabled = val&0xF == 0xA
+	case address < 0x4000:
+		mbc.romBank = val & 0x7F
+		if mbc.romBank == 0 {
+			mbc.romBank = 1
+		}
+	case address < 0x6000:
+		mbc.ramBank = val
+	default:
+		if mbc.rtc != nil {
+			mbc.rtc.writeLatch(val)
+		}
+	}
+}
+
+// Returns whether a clock register is selected instead of a RAM bank
+func (mbc *mbc3) rtcSelected() bool {
+	return mbc.rtc != nil && mbc.ramBank >= rtcSeconds && mbc.ramBank <= rtcDaysHigh
+}
+
+// Disabled RAM and banks that do not exist do not drive the bus
+func (mbc *mbc3) readRam(address uint16) uint8 {
+	if !mbc.ramEnabled {
+		return 0xFF
+	}
+	if mbc.rtcSelected() {
+		return mbc.rtc.read(mbc.ramBank)
+	}
+	if mbc.ramBank > 0x3 {
+		return 0xFF
+	}
+	return mbc.readRamBank(int(mbc.ramBank), address)
+}
+
+func (mbc *mbc3) writeRam(address uint16, val uint8) {
+	if !mbc.ramEnabled {
+		return
+	}
+	if mbc.rtcSelected() {
+		mbc.rtc.write(mbc.ramBank, val)
+	} else if mbc.ramBank <= 0x3 {
+		mbc.writeRamBank(int(mbc.ramBank), address, val)
+	}
+}
+
+func (mbc *mbc3) tick(cycles int) {
+	if mbc.rtc != nil {
+		mbc.rtc.tick(cycles)
+	}
+}
+
+func (mbc *mbc3) state(s *state) {
+	s.field(&mbc.ramEnabled)
+	s.field(&mbc.romBank)
+	s.field(&mbc.ramBank)
+	if mbc.rtc != nil {
+		mbc.rtc.state(s)
+	}
+}
+
+func (mbc *mbc3) String() string {
+	if mbc.rtcSelected() {
+		return fmt.Sprintf("MBC3 ROM bank %d, clock register %#02x, RAM enabled: %t", mbc.romBank, mbc.ramBank, mbc.ramEnabled)
+	}
+	return fmt.Sprintf("MBC3 ROM bank %d, RAM bank %d, RAM enabled: %t", mbc.romBank, mbc.ramBank, mbc.ramEnabled)
+}
@@ -0,0 +1,157 @@
+package gameboy
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Latches the clock and reads the clock register
+func readClock(gb *Gameboy, register uint8) uint8 {
+	gb.Write(0x6000, 0x00)
+	gb.Write(0x6000, 0x01)
+	gb.Write(0x4000, register)
+	return gb.Read(0xA000)
+}
+
+func TestMBC3Banks(t *testing.T) {
+	gb := bankedCart(0x10, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+
+	for _, test := range []struct{ val, bank uint8 }{{0x00, 1}, {0x3F, 0x3F}, {0xC2, 2}} {
+		gb.Write(0x2000, test.val)
+		if bank := gb.Read(0x4200); bank != test.bank {
+			t.Errorf("Expected %#02x to select bank %d, got %d", test.val, test.bank, bank)
+		}
+	}
+
+	for bank := uint8(0); bank < 4; bank++ {
+		gb.Write(0x4000, bank)
+		gb.Write(0xA123, 0x10+bank)
+	}
+	for bank := uint8(0); bank < 4; bank++ {
+		gb.Write(0x4000, bank)
+		if val := gb.Read(0xA123); val != 0x10+bank {
+			t.Errorf("Expected %#02x in RAM bank %d, got %#02x", 0x10+bank, bank, val)
+		}
+	}
+}
+
+func TestMBC3ClockLatch(t *testing.T) {
+	gb := bankedCart(0x10, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+	gb.Write(0x4000, rtcMinutes)
+	gb.Write(0xA000, 59)
+	gb.Write(0x4000, rtcSeconds)
+	gb.Write(0xA000, 59)
+
+	gb.mem.mbc.tick(ClockSpeed)
+	// The registers keep the latched value until the clock is latched again
+	gb.Write(0x4000, rtcMinutes)
+	if minutes := gb.Read(0xA000); minutes != 0 {
+		t.Errorf("Expected the registers to change only when latched, got %d minutes", minutes)
+	}
+	if seconds, minutes, hours := readClock(gb, rtcSeconds), readClock(gb, rtcMinutes), readClock(gb, rtcHours); seconds != 0 || minutes != 0 || hours != 1 {
+		t.Errorf("Expected 01:00:00, got %02d:%02d:%02d", hours, minutes, seconds)
+	}
+}
+
+func TestMBC3ClockHalt(t *testing.T) {
+	gb := bankedCart(0x10, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+	gb.Write(0x4000, rtcDaysHigh)
+	gb.Write(0xA000, 0x40)
+
+	gb.mem.mbc.tick(2 * ClockSpeed)
+	if seconds := readClock(gb, rtcSeconds); seconds != 0 {
+		t.Errorf("Expected a halted clock to stand still, got %d seconds", seconds)
+	}
+
+	gb.Write(0x4000, rtcDaysHigh)
+	gb.Write(0xA000, 0x00)
+	gb.mem.mbc.tick(2 * ClockSpeed)
+	if seconds := readClock(gb, rtcSeconds); seconds != 2 {
+		t.Errorf("Expected the clock to run again, got %d seconds", seconds)
+	}
+}
+
+func TestMBC3DayCarry(t *testing.T) {
+	gb := bankedCart(0x10, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+	gb.Write(0x4000, rtcHours)
+	gb.Write(0xA000, 23)
+	gb.Write(0x4000, rtcMinutes)
+	gb.Write(0xA000, 59)
+	gb.Write(0x4000, rtcSeconds)
+	gb.Write(0xA000, 59)
+	gb.Write(0x4000, rtcDaysLow)
+	gb.Write(0xA000, 0xFF)
+	gb.Write(0x4000, rtcDaysHigh)
+	gb.Write(0xA000, 0x01)
+
+	gb.mem.mbc.tick(ClockSpeed)
+	if days, high := readClock(gb, rtcDaysLow), readClock(gb, rtcDaysHigh); days != 0 || high != 0x80 {
+		t.Errorf("Expected day 0 with the carry set, got day %d and %#02x", days, high)
+	}
+}
+
+func TestRTCAdvance(t *testing.T) {
+	// Advancing at once matches advancing second by second, also from out of range values
+	for _, start := range []rtc{{}, {seconds: 61, minutes: 59, hours: 23, days: 511}, {seconds: 30, minutes: 62, hours: 30}} {
+		for _, seconds := range []int64{1, 59, 3600, 86400*3 + 7, 86400 * 600} {
+			expected, got := start, start
+			for i := int64(0); i < seconds; i++ {
+				expected.advanceSecond()
+			}
+			got.advance(seconds)
+			if got.registers() != expected.registers() {
+				t.Errorf("Advancing %+v by %d seconds gave %v instead of %v", start, seconds, got.registers(), expected.registers())
+			}
+		}
+	}
+}
+
+func TestMBC3SaveClock(t *testing.T) {
+	gb := bankedCart(0x10, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+	gb.Write(0x4000, rtcHours)
+	gb.Write(0xA000, 12)
+	gb.Write(0x4000, 0x00)
+	gb.Write(0xA000, 0x42)
+
+	buffer := &bytes.Buffer{}
+	if err := gb.SaveRAM(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if buffer.Len() != 32*1024+rtcFooterSize {
+		t.Errorf("Expected the clock after the RAM, got %d bytes", buffer.Len())
+	}
+
+	loaded := bankedCart(0x10, 0x05, 0x03, 64)
+	loaded.Write(0x0000, 0x0A)
+	if err := loaded.LoadRAM(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if hours := readClock(loaded, rtcHours); hours != 12 {
+		t.Errorf("Expected the clock to be loaded, got %d hours", hours)
+	}
+	loaded.Write(0x4000, 0x00)
+	if loaded.Read(0xA000) != 0x42 {
+		t.Errorf("Expected the RAM to be loaded")
+	}
+}
+
+func TestMBC3ClockMarksRAMChanged(t *testing.T) {
+	gb := bankedCart(0x10, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+	if err := gb.SaveRAM(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if gb.RAMChanged() {
+		t.Fatalf("Expected no changes directly after saving")
+	}
+
+	gb.mem.mbc.tick(ClockSpeed)
+	if !gb.RAMChanged() {
+		t.Errorf("Expected the clock to need saving after a second without RAM writes")
+	}
+}
@@ -0,0 +1,76 @@
+package gameboy
+
+import "fmt"
+
+/*
+MBC5 supports up to 8 MB of ROM with a 9-bit ROM bank and 128 kB of RAM in 16 banks. Unlike the other
+controllers it can map bank 0 at 0x4000. On rumble cartridges bit 3 of the RAM bank register drives
+the rumble motor, so only 8 RAM banks can be selected.
+*/
+type mbc5 struct {
+	banks
+	ramEnabled bool
+	romBank    uint16
+	ramBank    uint8
+	// Switches the rumble motor, nil on cartridges without one
+	rumble func(on bool)
+	motor  bool
+}
+
+func (mbc *mbc5) readRom(address uint16) uint8 {
+	if address < 0x4000 {
+		return mbc.readRomBank(0, address)
+	}
+	return mbc.readRomBank(int(mbc.romBank), address)
+}
+
+func (mbc *mbc5) writeRom(address uint16, val uint8) {
+	switch {
+	case address < 0x2000:
+		mbc.ramEnabled = val&0xF == 0xA
+	case address < 0x3000:
+		mbc.romBank = mbc.romBank&0x100 | uint16(val)
+	case address < 0x4000:
+		mbc.romBank = mbc.romBank&0xFF | uint16(val&0x1)<<8
+	case address < 0x6000:
+		if mbc.rumble != nil {
+			mbc.ramBank = val & 0x7
+			mbc.motor = testBit(val, 3)
+			mbc.rumble(mbc.motor)
+		} else {
+			mbc.ramBank = val & 0xF
+		}
+	}
+}
+
+// Disabled RAM does not drive the bus
+func (mbc *mbc5) readRam(address uint16) uint8 {
+	if !mbc.ramEnabled {
+		return 0xFF
+	}
+	return mbc.readRamBank(int(mbc.ramBank), address)
+}
+
+func (mbc *mbc5) writeRam(address uint16, val uint8) {
+	if mbc.ramEnabled {
+		mbc.writeRamBank(int(mbc.ramBank), address, val)
+	}
+}
+
+func (mbc *mbc5) state(s *state) {
+	s.field(&mbc.ramEnabled)
+	s.field(&mbc.romBank)
+	s.field(&mbc.ramBank)
+	s.field(&mbc.motor)
+	if mbc.rumble != nil {
+		mbc.rumble(mbc.motor)
+	}
+}
+
+func (mbc *mbc5) String() string {
+	description := fmt.Sprintf("MBC5 ROM bank %d, RAM bank %d, RAM enabled: %t", mbc.romBank, mbc.ramBank, mbc.ramEnabled)
+	if mbc.rumble != nil {
+		description += fmt.Sprintf(", rumble: %t", mbc.motor)
+	}
+	return description
+}
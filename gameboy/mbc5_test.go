@@ -0,0 +1,90 @@
+package gameboy
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMBC5RomBanks(t *testing.T) {
+	gb := bankedCart(0x1B, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+
+	gb.Write(0x2000, 0x00)
+	if bank := gb.Read(0x4200); bank != 0 {
+		t.Errorf("Expected bank 0 to be mapped at 0x4000, got %d", bank)
+	}
+	gb.Write(0x2000, 0x2A)
+	if bank := gb.Read(0x4200); bank != 0x2A {
+		t.Errorf("Expected bank 0x2A, got %#02x", bank)
+	}
+
+	gb.Write(0x3000, 0x01)
+	if bank := gb.mem.mbc.(*mbc5).romBank; bank != 0x12A {
+		t.Errorf("Expected 0x3000 to set the 9th bit, got bank %#03x", bank)
+	}
+	gb.Write(0x2FFF, 0x01)
+	if bank := gb.mem.mbc.(*mbc5).romBank; bank != 0x101 {
+		t.Errorf("Expected the lower bits to keep the 9th bit, got bank %#03x", bank)
+	}
+}
+
+func TestMBC5RamBanks(t *testing.T) {
+	gb := bankedCart(0x1B, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+
+	for bank := uint8(0); bank < 4; bank++ {
+		gb.Write(0x4000, bank)
+		gb.Write(0xB000, 0x50+bank)
+	}
+	gb.Write(0x4000, 0x02)
+	if val := gb.Read(0xB000); val != 0x52 {
+		t.Errorf("Expected %#02x in RAM bank 2, got %#02x", 0x52, val)
+	}
+
+	gb.Write(0x0000, 0x00)
+	if gb.Read(0xB000) != 0xFF {
+		t.Errorf("Expected disabled RAM to read 0xFF")
+	}
+}
+
+func TestMBC5Rumble(t *testing.T) {
+	gb := bankedCart(0x1E, 0x05, 0x03, 64)
+	gb.Write(0x0000, 0x0A)
+	var changes []bool
+	gb.SetRumble(func(on bool) {
+		changes = append(changes, on)
+	})
+
+	gb.Write(0x4000, 0x08)
+	gb.Write(0x4000, 0x09)
+	gb.Write(0xA000, 0x11)
+	gb.Write(0x4000, 0x01)
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("Expected the motor to be switched on and off once, got %v", changes)
+	}
+	if gb.Read(0xA000) != 0x11 {
+		t.Errorf("Expected bit 3 not to select a RAM bank on a rumble cartridge")
+	}
+
+	// Loading a state switches the motor to the state it was in
+	gb.Write(0x4000, 0x08)
+	buffer := &bytes.Buffer{}
+	gb.SaveState(buffer)
+	gb.Write(0x4000, 0x00)
+	changes = nil
+	if err := gb.LoadState(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || !changes[0] {
+		t.Errorf("Expected the motor to be switched on by loading the state, got %v", changes)
+	}
+}
+
+func TestMBC5LargeRom(t *testing.T) {
+	gb := bankedCart(0x19, 0x08, 0x00, 512)
+	gb.Write(0x2000, 0xFF)
+	gb.Write(0x3000, 0x01)
+	if bank := int(gb.Read(0x4201))<<8 | int(gb.Read(0x4200)); bank != 0x1FF {
+		t.Errorf("Expected the last of 512 banks, got %#03x", bank)
+	}
+}
@@ -0,0 +1,21 @@
+package gameboy
+
+/*
+Creates a Gameboy with a cartridge of the given type, header size codes and number of 16 kB banks.
+Each bank holds its number at 0x200, the low byte, and 0x201, the high byte.
+*/
+func bankedCart(cartType, romCode, ramCode uint8, banks int) *Gameboy {
+	rom := make([]uint8, banks*16*1024)
+	for bank := 0; bank < banks; bank++ {
+		rom[bank*16*1024+0x200] = uint8(bank)
+		rom[bank*16*1024+0x201] = uint8(bank >> 8)
+	}
+	rom[0x147] = cartType
+	rom[0x148] = romCode
+	rom[0x149] = ramCode
+	gb, err := New(rom, &Options{})
+	if err != nil {
+		panic(err)
+	}
+	return gb
+}
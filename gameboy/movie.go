@@ -0,0 +1,145 @@
+package gameboy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrMovieRom        = errors.New("movie was recorded with a different ROM")
+	ErrMovieNotPowerOn = errors.New("movie starts at power on")
+)
+
+const (
+	movieMagic   = "GBMV"
+	movieVersion = 1
+)
+
+/*
+A recording of the buttons that were pressed in every frame since power on or since a save state. A
+movie is recorded and played back by RunFrame, the buttons are set at the start of every frame.
+*/
+type Movie struct {
+	// The SHA-256 hash of the ROM the movie was recorded with
+	RomHash [sha256.Size]byte
+	// The save state the movie starts from, nil when it starts at power on
+	State []uint8
+	// The pressed buttons per frame, as a bitmask of ButtonA, ButtonB and so on
+	Frames []uint8
+}
+
+type movieHeader struct {
+	Magic   [4]byte
+	Version uint16
+	RomHash [sha256.Size]byte
+	State   uint32
+	Frames  uint32
+}
+
+// Writes the movie in the binary movie format: a versioned header, the save state and a byte per frame
+func (movie *Movie) Write(w io.Writer) error {
+	header := movieHeader{Version: movieVersion, RomHash: movie.RomHash, State: uint32(len(movie.State)), Frames: uint32(len(movie.Frames))}
+	copy(header.Magic[:], movieMagic)
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if _, err := w.Write(movie.State); err != nil {
+		return err
+	}
+	_, err := w.Write(movie.Frames)
+	return err
+}
+
+// Reads a movie that was written with Movie.Write
+func ReadMovie(r io.Reader) (*Movie, error) {
+	header := movieHeader{}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header.Magic[:], []byte(movieMagic)) {
+		return nil, errors.New("not a movie")
+	}
+	if header.Version != movieVersion {
+		return nil, fmt.Errorf("unsupported movie version %d", header.Version)
+	}
+
+	movie := &Movie{RomHash: header.RomHash, Frames: make([]uint8, header.Frames)}
+	if header.State != 0 {
+		movie.State = make([]uint8, header.State)
+		if _, err := io.ReadFull(r, movie.State); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := io.ReadFull(r, movie.Frames); err != nil {
+		return nil, err
+	}
+	return movie, nil
+}
+
+/*
+Starts recording the buttons of every frame run with RunFrame into a new movie, until StopMovie is
+called. A movie recorded after power on starts from a save state of the current state.
+*/
+func (gb *Gameboy) RecordMovie() *Movie {
+	gb.movie = &Movie{RomHash: gb.romHash()}
+	if gb.cycles != 0 {
+		gb.movie.State = gb.saveStateBytes()
+	}
+	gb.recording = true
+	return gb.movie
+}
+
+/*
+Plays back the movie from power on or from its save state, which is loaded first. Every frame run
+with RunFrame uses the buttons from the movie instead of the buttons set with SetInput, until the
+movie is finished or StopMovie is called.
+*/
+func (gb *Gameboy) PlayMovie(movie *Movie) error {
+	if movie.RomHash != gb.romHash() {
+		return ErrMovieRom
+	}
+	if movie.State != nil {
+		if err := gb.LoadState(bytes.NewReader(movie.State)); err != nil {
+			return err
+		}
+	} else if gb.cycles != 0 {
+		return ErrMovieNotPowerOn
+	}
+	gb.movie = movie
+	gb.recording = false
+	gb.movieFrame = 0
+	return nil
+}
+
+// Returns whether a movie is being played back
+func (gb *Gameboy) PlayingMovie() bool {
+	return gb.movie != nil && !gb.recording
+}
+
+// Stops recording or playing back the movie
+func (gb *Gameboy) StopMovie() {
+	gb.movie = nil
+	gb.recording = false
+}
+
+// Records or plays back the buttons for the frame that is about to start
+func (gb *Gameboy) updateMovie() {
+	if gb.movie == nil {
+		return
+	}
+	if gb.recording {
+		gb.movie.Frames = append(gb.movie.Frames, gb.mem.buttons)
+		return
+	}
+
+	if gb.movieFrame == len(gb.movie.Frames) {
+		gb.StopMovie()
+		return
+	}
+	gb.setButtons(gb.movie.Frames[gb.movieFrame])
+	gb.movieFrame++
+}
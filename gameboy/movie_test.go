@@ -0,0 +1,148 @@
+package gameboy
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Adds the joypad register with the direction keys selected to B in a loop
+var joypadSumCode = []uint8{0x3e, 0x20, 0xe0, 0x00, 0xf0, 0x00, 0x80, 0x47, 0x18, 0xf6}
+
+func TestMovieRoundTrip(t *testing.T) {
+	movie := &Movie{RomHash: [32]byte{1, 2, 3}, Frames: []uint8{0, ButtonA, ButtonStart | ButtonDown}}
+
+	buffer := &bytes.Buffer{}
+	if err := movie.Write(buffer); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadMovie(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.RomHash != movie.RomHash || !bytes.Equal(read.Frames, movie.Frames) {
+		t.Errorf("Expected %v, got %v", movie, read)
+	}
+
+	if _, err := ReadMovie(bytes.NewReader([]byte("not a movie at all, but long enough for a header"))); err == nil {
+		t.Errorf("Expected an error reading an invalid movie")
+	}
+}
+
+func TestMovieReplay(t *testing.T) {
+	gb := stepGameboy(joypadSumCode)
+	movie := gb.RecordMovie()
+	for _, buttons := range []uint8{0, ButtonRight, ButtonRight | ButtonUp, 0, ButtonDown, ButtonLeft} {
+		gb.SetButtons(buttons)
+		if _, _, err := gb.RunFrame(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gb.StopMovie()
+	if len(movie.Frames) != 6 || movie.Frames[2] != ButtonRight|ButtonUp {
+		t.Fatalf("Unexpected recorded frames %v", movie.Frames)
+	}
+
+	replay := stepGameboy(joypadSumCode)
+	if err := replay.PlayMovie(movie); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(movie.Frames); i++ {
+		// The movie overrides the input of the frontend
+		replay.SetButtons(ButtonA)
+		if _, _, err := replay.RunFrame(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if replay.cycles != gb.cycles || replay.reg.B != gb.reg.B {
+		t.Errorf("Replay diverged, B %#02x after %d cycles instead of %#02x after %d", replay.reg.B, replay.cycles, gb.reg.B, gb.cycles)
+	}
+
+	if !replay.PlayingMovie() {
+		t.Errorf("Expected the movie to play until the next frame")
+	}
+	replay.RunFrame()
+	if replay.PlayingMovie() {
+		t.Errorf("Expected the movie to stop after the last frame")
+	}
+}
+
+/*
+Input of the frontend during playback must not release and press a held button again, which would
+request a joypad interrupt. The interrupt requests are cleared before every frame to notice it.
+*/
+func TestMovieReplayIgnoresInput(t *testing.T) {
+	gb := stepGameboy(joypadSumCode)
+	movie := gb.RecordMovie()
+	for _, buttons := range []uint8{ButtonRight, ButtonRight, ButtonRight | ButtonUp, ButtonRight} {
+		gb.Write(0xFF0F, 0x00)
+		gb.SetButtons(buttons)
+		gb.RunFrame()
+	}
+	gb.StopMovie()
+
+	replay := stepGameboy(joypadSumCode)
+	if err := replay.PlayMovie(movie); err != nil {
+		t.Fatal(err)
+	}
+	for range movie.Frames {
+		replay.Write(0xFF0F, 0x00)
+		replay.SetInput(Input{Left: true})
+		replay.RunFrame()
+	}
+	if !bytes.Equal(replay.saveStateBytes(), gb.saveStateBytes()) {
+		t.Errorf("Replay diverged, IF %#02x instead of %#02x", replay.mem.ioPorts[0x0F], gb.mem.ioPorts[0x0F])
+	}
+}
+
+func TestMovieRequirements(t *testing.T) {
+	gb := stepGameboy(joypadSumCode)
+	movie := &Movie{}
+	if err := gb.PlayMovie(movie); err != ErrMovieRom {
+		t.Errorf("Expected ErrMovieRom, got %v", err)
+	}
+
+	movie = gb.RecordMovie()
+	gb.RunFrame()
+	gb.StopMovie()
+	if err := gb.PlayMovie(movie); err != ErrMovieNotPowerOn {
+		t.Errorf("Expected ErrMovieNotPowerOn, got %v", err)
+	}
+}
+
+func TestMovieFromSaveState(t *testing.T) {
+	gb := stepGameboy(joypadSumCode)
+	gb.SetButtons(ButtonUp)
+	gb.RunFrame()
+
+	movie := gb.RecordMovie()
+	if movie.State == nil {
+		t.Fatalf("Expected a movie recorded after power on to start from a save state")
+	}
+	for _, buttons := range []uint8{ButtonLeft, 0, ButtonDown} {
+		gb.SetButtons(buttons)
+		gb.RunFrame()
+	}
+	gb.StopMovie()
+
+	buffer := &bytes.Buffer{}
+	if err := movie.Write(buffer); err != nil {
+		t.Fatal(err)
+	}
+	movie, err := ReadMovie(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The save state is loaded over whatever the Gameboy was doing
+	replay := stepGameboy(joypadSumCode)
+	replay.RunFrame()
+	if err := replay.PlayMovie(movie); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		replay.RunFrame()
+	}
+	if replay.cycles != gb.cycles || replay.reg.B != gb.reg.B {
+		t.Errorf("Replay diverged, B %#02x after %d cycles instead of %#02x after %d", replay.reg.B, replay.cycles, gb.reg.B, gb.cycles)
+	}
+}
@@ -1,38 +1,53 @@
 package gameboy
 
-import (
-	"fmt"
-)
-
 type memory struct {
-	// TODO: Only MBC1 and MBC2 are supported now.
-	switchableRomBank [125][16 * 1024]uint8 // 0x000, 0x4000 (16 kB)
-	videoRam          [8 * 1024]uint8       // 0x8000 (8 kB)
-	// TODO: MBC2 has 512 x 4 bits.
-	switchableRamBank       [4][8 * 1024]uint8 // 0xA000 (8 kB)
-	internalRam8kb          [8 * 1024]uint8    // 0xC000 (8 kB)
-	echoInternalRam         [8 * 1024]uint8    // 0xE000 (8 kB)
-	spriteAttribMemory      [7680]uint8        // 0xFE00 (7680 B)
-	empty1                  [96]uint8          // 0xFEA0 (96 B)
-	ioPorts                 [76]uint8          // 0xFF00 (67 B)
-	empty2                  [52]uint8          // 0xFF4C (52 B)
-	internalRam             [127]uint8         // 0xFF80 (127 B)
-	interruptEnableRegister uint8              // 0xFFFF (1 B)
-	memorySettings          memorySettings
+	// The ROM banks of the cartridge, as many as the header declares
+	switchableRomBank [][16 * 1024]uint8 // 0x000, 0x4000 (16 kB)
+	videoRam          [8 * 1024]uint8    // 0x8000 (8 kB)
+	// The RAM banks of the cartridge, MBC2 uses the first 512 bytes for its half-bytes
+	switchableRamBank       [][8 * 1024]uint8 // 0xA000 (8 kB)
+	internalRam8kb          [8 * 1024]uint8   // 0xC000 (8 kB)
+	echoInternalRam         [8 * 1024]uint8   // 0xE000 (8 kB)
+	spriteAttribMemory      [7680]uint8       // 0xFE00 (7680 B)
+	empty1                  [96]uint8         // 0xFEA0 (96 B)
+	ioPorts                 [76]uint8         // 0xFF00 (67 B)
+	empty2                  [52]uint8         // 0xFF4C (52 B)
+	internalRam             [127]uint8        // 0xFF80 (127 B)
+	interruptEnableRegister uint8             // 0xFFFF (1 B)
+	mbc                     mbc
+	timer                   timer // 0xFF04 - 0xFF07
+	dma                     dma   // 0xFF46
+
+	// Advances the rest of the hardware on every CPU memory access in M-cycle mode, nil otherwise
+	clock *clock
+	// The first access to hardware that is not emulated since the last step
+	fault *Fault
+	// The pressed buttons, as a bitmask of ButtonA, ButtonB and so on
+	buttons uint8
+	// Set when the external RAM is written, so the frontend knows when to save it
+	ramChanged bool
+	// The rumble motor of the cartridge as the host sees it, nil when the host does not use it
+	motor *motor
 
 	depth int
 }
 
-type memorySettings struct {
-	mbc1 bool
-	mbc2 bool
-	mbc3 bool
+type clock struct {
+	tick func(cycles int)
+	// The number of cycles ticked by memory accesses during the current instruction
+	ticked int
+}
 
-	bankingMode uint8
-	ramEnabled  bool
+type motor struct {
+	rumble func(on bool)
+	on     bool
+}
 
-	currentROMBank uint8
-	currentRAMBank uint8
+// OAM DMA copies 160 bytes to the sprite attribute memory, one byte every M-cycle
+type dma struct {
+	active bool
+	source uint16
+	index  uint16
 }
 
 const (
@@ -50,11 +65,6 @@ const (
 	interruptEnableRegister
 )
 
-const (
-	romBankingMode = iota
-	ramBankingMode
-)
-
 var bootrom = []uint8{
 	0x31, 0xfe, 0xff, 0xaf, 0x21, 0xff, 0x9f, 0x32, 0xcb, 0x7c, 0x20, 0xfb,
 	0x21, 0x26, 0xff, 0x0e, 0x11, 0x3e, 0x80, 0x32, 0xe2, 0x0c, 0x3e, 0xf3,
@@ -80,24 +90,21 @@ var bootrom = []uint8{
 	0x3e, 0x01, 0xe0, 0x50}
 
 func memInit(cartridge []uint8, cartInfo *cartridgeInfo) *memory {
-	sw := [125][16 * 1024]uint8{}
-	for index, item := range bootrom {
-		sw[0][index] = item
+	// A ROM file that is smaller than its header says only has the banks in the file, which are mirrored
+	romBanks := cartInfo.romBanks()
+	if fileBanks := (len(cartridge) + 16*1024 - 1) / (16 * 1024); fileBanks < romBanks {
+		romBanks = fileBanks
 	}
-	for i := 0x100; i < len(sw[0]); i++ {
-		sw[0][i] = cartridge[i]
-	}
-
-	for bank := 1; bank < len(cartridge)/(16*1024); bank++ {
-		for j := 0; j < 16*1024; j++ {
-			sw[bank][j] = cartridge[bank*16*1024+j]
-		}
+	sw := make([][16 * 1024]uint8, romBanks)
+	for bank := range sw {
+		copy(sw[bank][:], cartridge[bank*16*1024:])
 	}
+	copy(sw[0][:], bootrom)
 
 	mem := &memory{
 		switchableRomBank:       sw,
 		videoRam:                [8 * 1024]uint8{},
-		switchableRamBank:       [4][8 * 1024]uint8{},
+		switchableRamBank:       make([][8 * 1024]uint8, (cartInfo.ramBytes()+8*1024-1)/(8*1024)),
 		internalRam8kb:          [8 * 1024]uint8{},
 		echoInternalRam:         [8 * 1024]uint8{},
 		spriteAttribMemory:      [7680]uint8{},
@@ -106,15 +113,8 @@ func memInit(cartridge []uint8, cartInfo *cartridgeInfo) *memory {
 		empty2:                  [52]uint8{},
 		internalRam:             [127]uint8{},
 		interruptEnableRegister: 0,
-		memorySettings: memorySettings{
-			mbc1:           cartInfo.mbc1,
-			mbc2:           cartInfo.mbc2,
-			mbc3:           cartInfo.mbc3,
-			currentROMBank: 1,
-			currentRAMBank: 0,
-			bankingMode:    romBankingMode,
-		},
 	}
+	mem.mbc = newMBC(cartInfo, mem)
 	return mem
 }
 
@@ -123,7 +123,7 @@ func mapAddr(addr uint16) int {
 		return bank0
 	} else if addr >= 0x4000 && addr < 0x8000 {
 		return switchableRomBank
-	} else if addr >= 8000 && addr < 0xA000 {
+	} else if addr >= 0x8000 && addr < 0xA000 {
 		return videoRam
 	} else if addr >= 0xA000 && addr < 0xC000 {
 		return switchableRamBank
@@ -141,39 +141,56 @@ func mapAddr(addr uint16) int {
 		return empty2
 	} else if addr >= 0xFF80 && addr < 0xFFFF {
 		return internalRam
-	} else if addr == 0xFFFF {
-		return interruptEnableRegister
 	} else {
-		panic(fmt.Sprintf("Unknown memory address %x\n", addr))
+		return interruptEnableRegister
 	}
 }
 
+// Records an access to hardware that is not emulated, the access itself is ignored
+func (memory *memory) raise(address uint16, reason string) {
+	if memory.fault == nil {
+		memory.fault = &Fault{Address: address, Reason: reason}
+	}
+}
+
+// Advances the rest of the hardware by one M-cycle before the CPU accesses memory
+func (memory *memory) cycle() {
+	if memory.clock != nil {
+		memory.clock.tick(4)
+		memory.clock.ticked += 4
+	}
+}
+
+// Reads a byte as the CPU does, which takes one M-cycle
 func (memory *memory) read8(address uint16) uint8 {
+	memory.cycle()
+	return memory.peek8(address)
+}
+
+// Reads a byte without advancing the rest of the hardware
+func (memory *memory) peek8(address uint16) uint8 {
 	switch mapAddr(address) {
-	case bank0:
-		return memory.switchableRomBank[0][address]
-	case switchableRomBank:
-		if memory.memorySettings.bankingMode == romBankingMode {
-			return memory.switchableRomBank[memory.memorySettings.currentROMBank][address-0x4000]
-		} else {
-			return memory.switchableRomBank[1][address-0x4000]
-		}
+	case bank0, switchableRomBank:
+		return memory.mbc.readRom(address)
 	case videoRam:
 		return memory.videoRam[address-0x8000]
 	case switchableRamBank:
-		if !memory.memorySettings.ramEnabled {
-			panic("External RAM not enabled before use")
-		}
-		if memory.memorySettings.bankingMode == ramBankingMode {
-			return memory.switchableRamBank[memory.memorySettings.currentRAMBank][address-0xA000]
-		} else {
-			return memory.switchableRamBank[0][address-0xA000]
-		}
+		return memory.mbc.readRam(address)
 	case internalRam8kb:
 		return memory.internalRam8kb[address-0xC000]
 	case echoInternalRam8kb:
 		return memory.internalRam8kb[address-0xE000]
+	case spriteAttribMemory:
+		return memory.spriteAttribMemory[address-0xFE00]
+	case empty1:
+		return memory.empty1[address-0xFEA0]
 	case ioPorts:
+		if address == 0xFF00 {
+			return memory.joypad()
+		}
+		if address >= 0xFF04 && address <= 0xFF07 {
+			return memory.timer.read(address)
+		}
 		return memory.ioPorts[address-0xFF00]
 	case empty2:
 		return memory.empty2[address-0xFF4C]
@@ -182,60 +199,39 @@ func (memory *memory) read8(address uint16) uint8 {
 	case interruptEnableRegister:
 		return memory.interruptEnableRegister
 	default:
-		panic(fmt.Sprintf("Read byte requested outside implemented memory: %x", address))
+		memory.raise(address, "read from unmapped memory")
+		return 0xFF
 	}
 }
 
+// Reads a little-endian halfword as two consecutive byte reads
 func (memory *memory) read16(address uint16) uint16 {
-	switch mapAddr(address) {
-	case bank0:
-		return uint16(memory.switchableRomBank[0][address]) | (uint16(memory.switchableRomBank[0][address+1]) << 8)
-	case switchableRomBank:
-		if memory.memorySettings.bankingMode == romBankingMode {
-			return uint16(memory.switchableRomBank[memory.memorySettings.currentROMBank][address-0x4000]) | (uint16(memory.switchableRomBank[memory.memorySettings.currentROMBank][address-0x4000+1]) << 8)
-		} else {
-			return uint16(memory.switchableRomBank[1][address-0x4000]) | (uint16(memory.switchableRomBank[1][address-0x4000+1]) << 8)
-		}
-	case switchableRamBank:
-		if !memory.memorySettings.ramEnabled {
-			panic("External RAM not enabled before use")
-		}
-		if memory.memorySettings.bankingMode == ramBankingMode {
-			return uint16(memory.switchableRamBank[memory.memorySettings.currentRAMBank][address-0xA000]) | uint16(memory.switchableRamBank[memory.memorySettings.currentRAMBank][address-0xA000+1])<<8
-		} else {
-			return uint16(memory.switchableRamBank[0][address-0xA000]) | uint16(memory.switchableRamBank[0][address-0xA000+1])<<8
-		}
-	case internalRam8kb:
-		return uint16(memory.internalRam8kb[address-0xC000]) | uint16(memory.internalRam8kb[address-0xC000+1])<<8
-	case ioPorts:
-		return uint16(memory.ioPorts[address-0xff00]) | (uint16(memory.ioPorts[address-0xff00+1]) << 8)
-	case internalRam:
-		return uint16(memory.internalRam[address-0xFF80]) | (uint16(memory.internalRam[address-0xFF80+1]) << 8)
-	default:
-		panic(fmt.Sprintf("Read halfword requested unimplemented memory: %x", address))
-	}
+	return uint16(memory.read8(address)) | uint16(memory.read8(address+1))<<8
+}
+
+func (memory *memory) peek16(address uint16) uint16 {
+	return uint16(memory.peek8(address)) | uint16(memory.peek8(address+1))<<8
 }
 
+// Writes a byte as the CPU does, which takes one M-cycle
 func (memory *memory) write8(address uint16, val uint8) {
+	memory.cycle()
+	memory.poke8(address, val)
+}
+
+// Writes a byte without advancing the rest of the hardware
+func (memory *memory) poke8(address uint16, val uint8) {
 	if memory.handleSpecificAddress(address, val) {
 		return
 	}
 	switch mapAddr(address) {
-	case bank0:
-		fallthrough
-	case switchableRomBank:
-		memory.doBankingAction(address, val)
+	case bank0, switchableRomBank:
+		memory.mbc.writeRom(address, val)
 	case videoRam:
 		memory.videoRam[address-0x8000] = val
 	case switchableRamBank:
-		if !memory.memorySettings.ramEnabled {
-			panic("External RAM not enabled before use")
-		}
-		if memory.memorySettings.bankingMode == ramBankingMode {
-			memory.switchableRamBank[memory.memorySettings.currentRAMBank][address-0xa000] = val
-		} else {
-			memory.switchableRamBank[0][address-0xa000] = val
-		}
+		memory.mbc.writeRam(address, val)
+		memory.ramChanged = true
 	case internalRam8kb:
 		memory.internalRam8kb[address-0xc000] = val
 	case echoInternalRam8kb:
@@ -254,12 +250,15 @@ func (memory *memory) write8(address uint16, val uint8) {
 	case interruptEnableRegister:
 		memory.interruptEnableRegister = val
 	default:
-		panic(fmt.Sprintf("Write byte not yet implemented for address: %#04x on %d", address, mapAddr(address)))
+		memory.raise(address, "write to unmapped memory")
 	}
 }
 
 func (memory *memory) handleSpecificAddress(address uint16, val uint8) bool {
 	switch address {
+	case 0xFF00:
+		memory.updateJoypad(val, memory.buttons)
+		return true
 	case 0xFF40:
 		memory.ioPorts[0x40] = val
 		return true
@@ -268,115 +267,67 @@ func (memory *memory) handleSpecificAddress(address uint16, val uint8) bool {
 		memory.ioPorts[0x44] = 0
 		return true
 	case 0xFF46:
-		for i := 0; i < 0xA0; i++ {
-			memory.spriteAttribMemory[i] = memory.read8(uint16(val)*0x100 + uint16(i))
+		memory.ioPorts[0x46] = val
+		source := uint16(val) << 8
+		if source >= 0xE000 {
+			// Sources above the internal RAM read from its echo
+			source -= 0x2000
 		}
+		memory.dma = dma{active: true, source: source}
 		return true
-	case 0xFF04:
-		fmt.Println("Reset divider to 0")
-		memory.ioPorts[0x04] = 0
-		return true
-	case 0xFF05:
-		fmt.Printf("Writing to counter: %#04x\n", val)
-		memory.ioPorts[0x05] = val
-		return true
-	case 0xFF06:
-		fmt.Printf("Writing to modulo: %#04x\n", val)
-		memory.ioPorts[0x06] = val
-		return true
-	case 0xFF07:
-		fmt.Printf("Writing to timer control: %b\n", val)
-		memory.ioPorts[0x07] = val
+	case 0xFF04, 0xFF05, 0xFF06, 0xFF07:
+		memory.timer.write(address, val)
 		return true
 	}
 	return false
 }
 
+// Writes a little-endian halfword as two consecutive byte writes
 func (memory *memory) write16(address uint16, val uint16) {
-	switch mapAddr(address) {
-	case bank0:
-		fallthrough
-	case switchableRomBank:
-		memory.doBankingAction(address, uint8(val))
-	case videoRam:
-		memory.internalRam8kb[address-0x8000] = uint8(val)
-		memory.internalRam8kb[address-0x8000+1] = uint8(val >> 8)
-	case internalRam8kb:
-		memory.internalRam8kb[address-0xC000] = uint8(val)
-		memory.internalRam8kb[address-0xC000+1] = uint8(val >> 8)
-	default:
-		panic(fmt.Sprintf("Write halfword not yet implemented for address %#04x", address))
-	}
+	memory.write8(address, uint8(val))
+	memory.write8(address+1, uint8(val>>8))
 }
 
-func (memory *memory) doBankingAction(address uint16, val uint8) {
-	settings := memory.memorySettings
-
-	if !settings.mbc1 && !settings.mbc2 && !settings.mbc3 {
-		fmt.Println("Not performing banking?")
-		return
+/*
+Returns the joypad register as it reads at this moment. Writing 0 to bit 4 selects the directions
+and writing 0 to bit 5 the action buttons, a pressed button on a selected line reads 0.
+*/
+func (memory *memory) joypad() uint8 {
+	// Only the selection bits are stored, the unused upper bits read as 1
+	joypad := memory.ioPorts[0x00]&0x30 | 0xCF
+	if !testBit(joypad, 4) {
+		joypad &^= memory.buttons >> 4
 	}
-
-	if settings.mbc1 {
-		memory.mbc1BankingAction(address, val)
-	} else if settings.mbc2 {
-		memory.mbc2BankingAction(address, val)
-	} else if settings.mbc3 {
-		memory.mbc3BankingAction(address, val)
-	} else {
-		panic("Banking not implemented for MBC chip type")
+	if !testBit(joypad, 5) {
+		joypad &^= memory.buttons & 0xF
 	}
+	return joypad
 }
 
-func (memory *memory) mbc1BankingAction(address uint16, val uint8) {
-	if address < 0x1FFF {
-		if val&0xA == 0xA {
-			fmt.Println("Enabling external RAM")
-			memory.memorySettings.ramEnabled = true
-		} else {
-			fmt.Println("Enabling disabling RAM")
-			memory.memorySettings.ramEnabled = false
-		}
-	} else if address >= 0x2000 && address < 0x4000 {
-		fmt.Printf("Set current ROM bank: %d\n", val & 0x1F)
-		memory.memorySettings.currentROMBank = val & 0x1F
-	} else if address >= 0x4000 && address < 0x6000 {
-		if memory.memorySettings.bankingMode == romBankingMode {
-			fmt.Printf("Set upper bits of ROM bank: %d\n", (val&0x3)<<5)
-			memory.memorySettings.currentROMBank = memory.memorySettings.currentROMBank | (val&0x3)<<5
-		} else {
-			fmt.Printf("Set RAM bank: %d\n", val & 0x3)
-			memory.memorySettings.currentRAMBank = val & 0x3
-		}
-		if memory.memorySettings.currentROMBank == 00 || memory.memorySettings.currentROMBank == 0x20 || memory.memorySettings.currentROMBank == 0x40 || memory.memorySettings.currentROMBank == 0x60 {
-			memory.memorySettings.currentROMBank++
-		}
-	} else if address >= 0x6000 && address < 0x8000 {
-		if val == 0x1 {
-			fmt.Println("RAM banking mode")
-			memory.memorySettings.bankingMode = ramBankingMode
-		} else if val == 0x0 {
-			fmt.Println("ROM banking mode")
-			memory.memorySettings.bankingMode = romBankingMode
-		}
+// Changes the selected lines and the pressed buttons. A button line going low requests the joypad interrupt.
+func (memory *memory) updateJoypad(selection uint8, buttons uint8) {
+	before := memory.joypad()
+	memory.ioPorts[0x00] = selection & 0x30
+	memory.buttons = buttons
+	if before&^memory.joypad()&0xF != 0 {
+		memory.ioPorts[0x0F] = setBit(memory.ioPorts[0x0F], 4)
 	}
 }
 
-func (memory *memory) mbc2BankingAction(address uint16, val uint8) {
-	if address <= 0x1FFF {
-		panic("Disable/enable RAM not implemented")
-		// Disable/enable RAM
-	} else if address >= 0x2000 && address <= 0x3FFF {
-		// Select ROM number if 9th bit is 1
-		if address&(0x1<<8) == 0x100 {
-			fmt.Printf("Swapping ROM to %d\n", val&0xf)
-			memory.memorySettings.currentROMBank = val & 0xf
-		}
-	}
+// Returns whether an enabled interrupt is requested, regardless of the interrupt master enable
+func (memory *memory) interruptPending() bool {
+	return memory.interruptEnableRegister&memory.ioPorts[0x0F]&0x1F != 0
 }
 
-func (memory *memory) mbc3BankingAction(address uint16, val uint8) {
-	panic("MBC3 banking not implemented")
+// Copies a byte to the sprite attribute memory for every M-cycle while an OAM DMA is active
+func (memory *memory) updateDMA(cycles int) {
+	for ; cycles > 0 && memory.dma.active; cycles -= 4 {
+		memory.spriteAttribMemory[memory.dma.index] = memory.peek8(memory.dma.source + memory.dma.index)
+		memory.dma.index++
+		if memory.dma.index == 0xA0 {
+			memory.dma.active = false
+		}
+	}
 }
 
 func (memory *memory) swapBootRom(cartridge []uint8) {
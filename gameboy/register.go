@@ -14,6 +14,18 @@ type register struct {
 
 	SP uint16
 	PC uint16
+
+	// CPU control state. It is kept next to the registers so the instructions that change it
+	// (EI, DI, RETI, HALT and STOP) can do so themselves.
+	interruptMaster          bool
+	interruptEnableScheduled bool
+	halted                   bool
+	stopped                  bool
+	// Set when HALT is executed with interrupts disabled while one is already pending. The CPU
+	// then does not halt, but fails to increment the PC after reading the next opcode.
+	haltBug bool
+	// Set by illegal opcodes. The CPU stops executing until it is reset.
+	locked bool
 }
 
 type duoRegister int
@@ -0,0 +1,222 @@
+package gameboy
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+/*
+The real-time clock of MBC3 cartridges. It counts seconds, minutes, hours and a 9-bit day counter,
+which sets the carry bit when it overflows. The game reads a copy of the counters that is latched by
+writing 0 and then 1 to 0x6000-0x7FFF.
+
+The clock runs on the emulated time, a second every ClockSpeed cycles, so it is paused and fast-
+forwarded with the emulation. With Options.HostClock it follows the time of the host instead.
+*/
+type rtc struct {
+	seconds uint8
+	minutes uint8
+	hours   uint8
+	days    uint16
+	halted  bool
+	carry   bool
+
+	// The cycles since the last second
+	cycles int
+	// The registers as they were when the clock was latched, in register order
+	latched [5]uint8
+	// The last value written to the latch register
+	latch uint8
+
+	// The host time up to which the clock was advanced, zero when running on the emulated time
+	synced time.Time
+	// Whether the counters changed since the clock was last saved, the .sav file has to be written again
+	changed bool
+}
+
+// The clock registers 0x08 to 0x0C, which are selected as the RAM bank
+const (
+	rtcSeconds = 0x08 + iota
+	rtcMinutes
+	rtcHours
+	rtcDaysLow
+	rtcDaysHigh
+)
+
+func (rtc *rtc) tick(cycles int) {
+	if !rtc.synced.IsZero() || rtc.halted {
+		return
+	}
+	rtc.cycles += cycles
+	for rtc.cycles >= ClockSpeed {
+		rtc.cycles -= ClockSpeed
+		rtc.advanceSecond()
+	}
+}
+
+// Catches up with the time of the host
+func (rtc *rtc) sync() {
+	if rtc.synced.IsZero() {
+		return
+	}
+	seconds := int64(time.Since(rtc.synced) / time.Second)
+	if seconds <= 0 {
+		return
+	}
+	rtc.synced = rtc.synced.Add(time.Duration(seconds) * time.Second)
+	if !rtc.halted {
+		rtc.advance(seconds)
+	}
+}
+
+/*
+The counters are 6 bits for seconds and minutes and 5 bits for hours. A game can write a value that is
+out of range, which counts up to the end of the bits and then wraps to 0 without a carry.
+*/
+func (rtc *rtc) advanceSecond() {
+	rtc.changed = true
+	rtc.seconds = (rtc.seconds + 1) & 0x3F
+	if rtc.seconds != 60 {
+		return
+	}
+	rtc.seconds = 0
+	rtc.minutes = (rtc.minutes + 1) & 0x3F
+	if rtc.minutes != 60 {
+		return
+	}
+	rtc.minutes = 0
+	rtc.hours = (rtc.hours + 1) & 0x1F
+	if rtc.hours != 24 {
+		return
+	}
+	rtc.hours = 0
+	rtc.days++
+	if rtc.days == 512 {
+		rtc.days = 0
+		rtc.carry = true
+	}
+}
+
+// Advances the clock by a number of seconds, for example the time the host was off
+func (rtc *rtc) advance(seconds int64) {
+	for ; seconds > 0 && (rtc.seconds >= 60 || rtc.minutes >= 60 || rtc.hours >= 24); seconds-- {
+		rtc.advanceSecond()
+	}
+	if seconds <= 0 {
+		return
+	}
+
+	rtc.changed = true
+	total := int64(rtc.seconds) + 60*int64(rtc.minutes) + 3600*int64(rtc.hours) + 86400*int64(rtc.days) + seconds
+	rtc.seconds = uint8(total % 60)
+	rtc.minutes = uint8(total / 60 % 60)
+	rtc.hours = uint8(total / 3600 % 24)
+	days := total / 86400
+	if days >= 512 {
+		rtc.carry = true
+	}
+	rtc.days = uint16(days % 512)
+}
+
+func (rtc *rtc) registers() [5]uint8 {
+	daysHigh := uint8(rtc.days>>8) & 0x1
+	if rtc.halted {
+		daysHigh |= 1 << 6
+	}
+	if rtc.carry {
+		daysHigh |= 1 << 7
+	}
+	return [5]uint8{rtc.seconds, rtc.minutes, rtc.hours, uint8(rtc.days), daysHigh}
+}
+
+func (rtc *rtc) read(register uint8) uint8 {
+	return rtc.latched[register-rtcSeconds]
+}
+
+func (rtc *rtc) write(register uint8, val uint8) {
+	rtc.sync()
+	rtc.changed = true
+	switch register {
+	case rtcSeconds:
+		// Writing the seconds restarts the current second
+		rtc.seconds = val & 0x3F
+		rtc.cycles = 0
+	case rtcMinutes:
+		rtc.minutes = val & 0x3F
+	case rtcHours:
+		rtc.hours = val & 0x1F
+	case rtcDaysLow:
+		rtc.days = rtc.days&0x100 | uint16(val)
+	case rtcDaysHigh:
+		rtc.days = rtc.days&0xFF | uint16(val&0x1)<<8
+		rtc.halted = testBit(val, 6)
+		rtc.carry = testBit(val, 7)
+	}
+}
+
+// Writing 0 and then 1 copies the counters to the registers the game reads
+func (rtc *rtc) writeLatch(val uint8) {
+	if rtc.latch == 0 && val == 1 {
+		rtc.sync()
+		rtc.latched = rtc.registers()
+		rtc.changed = true
+	}
+	rtc.latch = val
+}
+
+func (rtc *rtc) state(s *state) {
+	s.field(&rtc.seconds)
+	s.field(&rtc.minutes)
+	s.field(&rtc.hours)
+	s.field(&rtc.days)
+	s.field(&rtc.halted)
+	s.field(&rtc.carry)
+	s.int(&rtc.cycles)
+	s.field(&rtc.latched)
+	s.field(&rtc.latch)
+}
+
+/*
+Other emulators store the clock after the RAM in the .sav file: the five registers and the five
+latched registers as 32-bit values, followed by the time of the host as a 64-bit UNIX timestamp.
+*/
+const rtcFooterSize = 48
+
+func (rtc *rtc) footer() []uint8 {
+	rtc.sync()
+	footer := make([]uint8, rtcFooterSize)
+	for i, val := range rtc.registers() {
+		binary.LittleEndian.PutUint32(footer[i*4:], uint32(val))
+	}
+	for i, val := range rtc.latched {
+		binary.LittleEndian.PutUint32(footer[20+i*4:], uint32(val))
+	}
+	binary.LittleEndian.PutUint64(footer[40:], uint64(time.Now().Unix()))
+	rtc.changed = false
+	return footer
+}
+
+/*
+Restores the clock from a footer written by footer or another emulator. Some emulators store the
+timestamp in 32 bits, which makes the footer 44 bytes. On the host time the clock catches up with the
+time that passed since the footer was written.
+*/
+func (rtc *rtc) loadFooter(footer []uint8) {
+	for i := range rtc.latched {
+		rtc.latched[i] = uint8(binary.LittleEndian.Uint32(footer[20+i*4:]))
+	}
+	for i := 0; i < 5; i++ {
+		rtc.write(rtcSeconds+uint8(i), uint8(binary.LittleEndian.Uint32(footer[i*4:])))
+	}
+
+	var saved int64
+	if len(footer) >= rtcFooterSize {
+		saved = int64(binary.LittleEndian.Uint64(footer[40:]))
+	} else {
+		saved = int64(binary.LittleEndian.Uint32(footer[40:]))
+	}
+	if !rtc.synced.IsZero() && !rtc.halted {
+		rtc.advance(time.Now().Unix() - saved)
+	}
+	rtc.changed = false
+}
@@ -0,0 +1,192 @@
+package gameboy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrStateRom = errors.New("save state was made with a different ROM")
+
+const (
+	stateMagic   = "GBST"
+	stateVersion = 1
+)
+
+type stateHeader struct {
+	Magic   [4]byte
+	Version uint16
+	RomHash [sha256.Size]byte
+}
+
+/*
+Reads or writes the machine state field by field, so the fields only have to be listed once. The
+first error is kept and all fields after it are skipped.
+*/
+type state struct {
+	w   io.Writer
+	r   io.Reader
+	err error
+}
+
+func (s *state) field(data interface{}) {
+	if s.err != nil {
+		return
+	}
+	if s.w != nil {
+		s.err = binary.Write(s.w, binary.LittleEndian, data)
+	} else {
+		s.err = binary.Read(s.r, binary.LittleEndian, data)
+	}
+}
+
+// Ints have no fixed size, they are stored as 64 bits
+func (s *state) int(data *int) {
+	value := int64(*data)
+	s.field(&value)
+	*data = int(value)
+}
+
+// Writes the complete machine state, it can be restored with LoadState on a Gameboy with the same ROM
+func (gb *Gameboy) SaveState(w io.Writer) error {
+	header := stateHeader{Version: stateVersion, RomHash: gb.romHash()}
+	copy(header.Magic[:], stateMagic)
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	s := &state{w: w}
+	gb.state(s)
+	return s.err
+}
+
+/*
+Restores the machine state written by SaveState. The state is left unchanged when an error is
+returned. A movie that is recorded or played back is stopped.
+*/
+func (gb *Gameboy) LoadState(r io.Reader) error {
+	header := stateHeader{}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if !bytes.Equal(header.Magic[:], []byte(stateMagic)) {
+		return errors.New("not a save state")
+	}
+	if header.Version != stateVersion {
+		return fmt.Errorf("unsupported save state version %d", header.Version)
+	}
+	if header.RomHash != gb.romHash() {
+		return ErrStateRom
+	}
+
+	// The state is read into new hardware, which replaces the current hardware when it is complete
+	loaded := *gb
+	loaded.mem = memInit(gb.cartridge, gb.cartridgeInfo)
+	loaded.mem.clock = gb.mem.clock
+	loaded.mem.motor = gb.mem.motor
+	loaded.graphics = createGraphics(loaded.mem.videoRam[:], loaded.mem.ioPorts[:], loaded.mem.spriteAttribMemory[:], gb.graphics.sink)
+	loaded.reg = new(register)
+
+	s := &state{r: r}
+	loaded.state(s)
+	if s.err != nil {
+		return s.err
+	}
+	if loaded.bootromSwapped {
+		loaded.mem.swapBootRom(gb.cartridge)
+	}
+	// The external RAM of the state has not been saved by the frontend
+	loaded.mem.ramChanged = true
+	if gb.options.HostClock {
+		loaded.mem.useHostClock()
+	}
+
+	gb.mem = loaded.mem
+	gb.graphics = loaded.graphics
+	gb.reg = loaded.reg
+	gb.cycles = loaded.cycles
+	gb.bootromSwapped = loaded.bootromSwapped
+	gb.StopMovie()
+	return nil
+}
+
+// Returns the bytes of a save state of the current machine state
+func (gb *Gameboy) saveStateBytes() []uint8 {
+	buffer := &bytes.Buffer{}
+	// Writes to a buffer do not fail
+	gb.SaveState(buffer)
+	return buffer.Bytes()
+}
+
+func (gb *Gameboy) romHash() [sha256.Size]byte {
+	return sha256.Sum256(gb.cartridge)
+}
+
+// Lists the fields of the save state. Adding, removing or reordering fields needs a new stateVersion.
+func (gb *Gameboy) state(s *state) {
+	s.field(&gb.cycles)
+	s.field(&gb.bootromSwapped)
+	gb.reg.state(s)
+	gb.mem.state(s)
+	gb.graphics.state(s)
+}
+
+func (reg *register) state(s *state) {
+	s.field(&reg.A)
+	s.field(&reg.B)
+	s.field(&reg.C)
+	s.field(&reg.D)
+	s.field(&reg.E)
+	s.field(&reg.F)
+	s.field(&reg.H)
+	s.field(&reg.L)
+	s.field(&reg.SP)
+	s.field(&reg.PC)
+	s.field(&reg.interruptMaster)
+	s.field(&reg.interruptEnableScheduled)
+	s.field(&reg.halted)
+	s.field(&reg.stopped)
+	s.field(&reg.haltBug)
+	s.field(&reg.locked)
+}
+
+// The ROM banks are not part of the state, they are loaded from the cartridge
+func (memory *memory) state(s *state) {
+	s.field(&memory.videoRam)
+	// The RAM banks are sized by the cartridge header, which is the same for the same ROM
+	s.field(memory.switchableRamBank)
+	s.field(&memory.internalRam8kb)
+	s.field(&memory.echoInternalRam)
+	s.field(&memory.spriteAttribMemory)
+	s.field(&memory.empty1)
+	s.field(&memory.ioPorts)
+	s.field(&memory.empty2)
+	s.field(&memory.internalRam)
+	s.field(&memory.interruptEnableRegister)
+	s.field(&memory.buttons)
+
+	memory.mbc.state(s)
+
+	s.field(&memory.timer.counter)
+	s.field(&memory.timer.tima)
+	s.field(&memory.timer.tma)
+	s.field(&memory.timer.tac)
+	s.field(&memory.timer.overflowed)
+	s.field(&memory.timer.reloaded)
+
+	s.field(&memory.dma.active)
+	s.field(&memory.dma.source)
+	s.field(&memory.dma.index)
+}
+
+func (graphics *graphics) state(s *state) {
+	s.field(&graphics.screen)
+	s.field(&graphics.frame)
+	s.int(&graphics.frames)
+	s.int(&graphics.mode)
+	s.int(&graphics.modeclock)
+	s.field(&graphics.line)
+}
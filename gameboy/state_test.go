@@ -0,0 +1,77 @@
+package gameboy
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSaveStateRoundTrip(t *testing.T) {
+	gb := stepGameboy(joypadSumCode)
+	gb.SetButtons(ButtonRight)
+	gb.RunFrame()
+
+	buffer := &bytes.Buffer{}
+	if err := gb.SaveState(buffer); err != nil {
+		t.Fatal(err)
+	}
+	saved := buffer.Bytes()
+
+	gb.SetButtons(ButtonDown)
+	gb.RunFrame()
+	cycles, b, frame := gb.cycles, gb.reg.B, *gb.Frame()
+
+	if err := gb.LoadState(bytes.NewReader(saved)); err != nil {
+		t.Fatal(err)
+	}
+	if gb.reg.PC < 0xc000 || gb.mem.buttons != ButtonRight {
+		t.Errorf("State not restored, PC %#04x buttons %#02x", gb.reg.PC, gb.mem.buttons)
+	}
+	gb.SetButtons(ButtonDown)
+	gb.RunFrame()
+	if gb.cycles != cycles || gb.reg.B != b || *gb.Frame() != frame {
+		t.Errorf("Emulation diverged after loading, B %#02x after %d cycles instead of %#02x after %d", gb.reg.B, gb.cycles, b, cycles)
+	}
+}
+
+func TestLoadStateBootRom(t *testing.T) {
+	gb, _ := New(make([]uint8, 32*1024), nil)
+	gb.RunFrame()
+	buffer := &bytes.Buffer{}
+	gb.SaveState(buffer)
+
+	// The boot ROM is mapped again when the state was saved before it was swapped out
+	loaded := stepGameboy(nil)
+	if err := loaded.LoadState(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.bootromSwapped || loaded.Read(0x0000) != bootrom[0] {
+		t.Errorf("Boot ROM not mapped after loading, read %#02x", loaded.Read(0x0000))
+	}
+}
+
+func TestLoadStateErrors(t *testing.T) {
+	gb := stepGameboy(joypadSumCode)
+	buffer := &bytes.Buffer{}
+	gb.SaveState(buffer)
+	saved := buffer.Bytes()
+
+	other := make([]uint8, 32*1024)
+	other[0x134] = 'X'
+	otherGb, _ := New(other, &Options{})
+	if err := otherGb.LoadState(bytes.NewReader(saved)); err != ErrStateRom {
+		t.Errorf("Expected ErrStateRom, got %v", err)
+	}
+
+	gb.RunFrame()
+	pc, cycles := gb.reg.PC, gb.cycles
+	if err := gb.LoadState(bytes.NewReader(saved[:len(saved)-1])); err == nil {
+		t.Errorf("Expected an error loading a truncated state")
+	}
+	if gb.reg.PC != pc || gb.cycles != cycles {
+		t.Errorf("State changed by a failed load")
+	}
+
+	if err := gb.LoadState(bytes.NewReader([]byte("GBMV not a save state at all, but long enough"))); err == nil {
+		t.Errorf("Expected an error loading a movie as a state")
+	}
+}
@@ -0,0 +1,106 @@
+package gameboy
+
+/*
+The timer is driven by a 16-bit system counter that is incremented every cycle. DIV is the upper
+byte of this counter. TIMA is incremented on the falling edge of one of the counter bits, selected
+by TAC and ANDed with the timer enable bit. This is why writes to DIV and TAC can increment TIMA.
+*/
+type timer struct {
+	counter uint16
+	tima    uint8
+	tma     uint8
+	tac     uint8
+
+	// TIMA overflowed during the last M-cycle and reads 0x00 until TMA is reloaded
+	overflowed bool
+	// TIMA was reloaded from TMA during the last M-cycle
+	reloaded bool
+}
+
+// The counter bit selected by the lower two TAC bits, for 4096 Hz, 262144 Hz, 65536 Hz and 16384 Hz
+var timerCounterBits = [4]uint16{1 << 9, 1 << 3, 1 << 5, 1 << 7}
+
+// Advances the timer by the given number of cycles. Returns whether a timer interrupt is requested.
+func (timer *timer) tick(cycles int) bool {
+	interrupt := false
+	for ; cycles > 0; cycles -= 4 {
+		if timer.step() {
+			interrupt = true
+		}
+	}
+	return interrupt
+}
+
+// Advances the timer by one M-cycle. Returns whether TIMA was reloaded from TMA.
+func (timer *timer) step() bool {
+	timer.reloaded = false
+	if timer.overflowed {
+		// The reload and the interrupt request happen one M-cycle after the overflow
+		timer.overflowed = false
+		timer.reloaded = true
+		timer.tima = timer.tma
+	}
+	timer.setCounter(timer.counter + 4)
+	return timer.reloaded
+}
+
+// The input to the falling edge detector that increments TIMA
+func (timer *timer) input() bool {
+	return testBit(timer.tac, 2) && timer.counter&timerCounterBits[timer.tac&0x3] != 0
+}
+
+func (timer *timer) setCounter(val uint16) {
+	before := timer.input()
+	timer.counter = val
+	if before && !timer.input() {
+		timer.increment()
+	}
+}
+
+func (timer *timer) increment() {
+	timer.tima++
+	if timer.tima == 0 {
+		timer.overflowed = true
+	}
+}
+
+func (timer *timer) read(address uint16) uint8 {
+	switch address {
+	case 0xFF04:
+		return uint8(timer.counter >> 8)
+	case 0xFF05:
+		return timer.tima
+	case 0xFF06:
+		return timer.tma
+	default:
+		// The upper five bits of TAC are unused and always read as 1
+		return timer.tac | 0xF8
+	}
+}
+
+func (timer *timer) write(address uint16, val uint8) {
+	switch address {
+	case 0xFF04:
+		// Any write resets the whole counter, which can be seen as a falling edge
+		timer.setCounter(0)
+	case 0xFF05:
+		// Writes during the reload cycle are ignored, writes before it cancel the reload
+		if !timer.reloaded {
+			timer.tima = val
+			timer.overflowed = false
+		}
+	case 0xFF06:
+		timer.tma = val
+		// TMA is still being copied to TIMA during the reload cycle
+		if timer.reloaded {
+			timer.tima = val
+		}
+	case 0xFF07:
+		// Disabling the timer or selecting another bit can be seen as a falling edge as well
+		before := timer.input()
+		timer.tac = val & 0x7
+		if before && !timer.input() {
+			timer.increment()
+		}
+	}
+}
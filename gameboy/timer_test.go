@@ -0,0 +1,126 @@
+package gameboy
+
+import "testing"
+
+func TestDivIsUpperCounterByte(t *testing.T) {
+	mem := dummyMemory()
+	mem.timer.tick(256 * 3)
+
+	if div := mem.read8(0xFF04); div != 3 {
+		t.Errorf("Expected DIV to be 3, got %d", div)
+	}
+
+	mem.write8(0xFF04, 0x12)
+	if div := mem.read8(0xFF04); div != 0 || mem.timer.counter != 0 {
+		t.Errorf("Expected a DIV write to reset the counter, got DIV %d and counter %#04x", div, mem.timer.counter)
+	}
+}
+
+func TestTimaIncrementsOnFallingEdge(t *testing.T) {
+	mem := dummyMemory()
+	mem.write8(0xFF07, 0x5) // Enabled, 262144 Hz
+
+	mem.timer.tick(12)
+	if tima := mem.read8(0xFF05); tima != 0 {
+		t.Errorf("Expected TIMA to be 0 before the falling edge, got %d", tima)
+	}
+
+	mem.timer.tick(4)
+	if tima := mem.read8(0xFF05); tima != 1 {
+		t.Errorf("Expected TIMA to be 1 after 16 cycles, got %d", tima)
+	}
+}
+
+func TestTimaReloadIsDelayed(t *testing.T) {
+	mem := dummyMemory()
+	mem.write8(0xFF06, 0xAB)
+	mem.write8(0xFF05, 0xFF)
+	mem.write8(0xFF07, 0x5)
+
+	if mem.timer.tick(16) {
+		t.Errorf("Expected no interrupt in the cycle TIMA overflows")
+	}
+	if tima := mem.read8(0xFF05); tima != 0 {
+		t.Errorf("Expected TIMA to read 0 directly after overflowing, got %#02x", tima)
+	}
+
+	if !mem.timer.tick(4) {
+		t.Errorf("Expected an interrupt one M-cycle after the overflow")
+	}
+	if tima := mem.read8(0xFF05); tima != 0xAB {
+		t.Errorf("Expected TIMA to be reloaded with TMA, got %#02x", tima)
+	}
+}
+
+func TestTimaWriteCancelsReload(t *testing.T) {
+	mem := dummyMemory()
+	mem.write8(0xFF06, 0xAB)
+	mem.write8(0xFF05, 0xFF)
+	mem.write8(0xFF07, 0x5)
+
+	mem.timer.tick(16)
+	mem.write8(0xFF05, 0x42)
+
+	if mem.timer.tick(4) {
+		t.Errorf("Expected the TIMA write to cancel the interrupt")
+	}
+	if tima := mem.read8(0xFF05); tima != 0x42 {
+		t.Errorf("Expected TIMA to keep the written value, got %#02x", tima)
+	}
+}
+
+func TestTimaWriteDuringReloadIsIgnored(t *testing.T) {
+	mem := dummyMemory()
+	mem.write8(0xFF06, 0xAB)
+	mem.write8(0xFF05, 0xFF)
+	mem.write8(0xFF07, 0x5)
+
+	mem.timer.tick(20)
+	mem.write8(0xFF05, 0x42)
+	if tima := mem.read8(0xFF05); tima != 0xAB {
+		t.Errorf("Expected the TIMA write in the reload cycle to be ignored, got %#02x", tima)
+	}
+
+	mem.write8(0xFF06, 0xCD)
+	if tima := mem.read8(0xFF05); tima != 0xCD {
+		t.Errorf("Expected the TMA write in the reload cycle to be copied to TIMA, got %#02x", tima)
+	}
+}
+
+func TestDivResetIncrementsTima(t *testing.T) {
+	mem := dummyMemory()
+	mem.write8(0xFF07, 0x4) // Enabled, 4096 Hz uses counter bit 9
+	mem.timer.tick(0x200)
+
+	mem.write8(0xFF04, 0)
+	if tima := mem.read8(0xFF05); tima != 1 {
+		t.Errorf("Expected resetting DIV with bit 9 set to increment TIMA, got %d", tima)
+	}
+}
+
+func TestTacChangeIncrementsTima(t *testing.T) {
+	mem := dummyMemory()
+	mem.write8(0xFF07, 0x5)
+	mem.timer.tick(8)
+
+	// Bit 3 is set, disabling the timer is a falling edge
+	mem.write8(0xFF07, 0x1)
+	if tima := mem.read8(0xFF05); tima != 1 {
+		t.Errorf("Expected disabling the timer to increment TIMA, got %d", tima)
+	}
+	if tac := mem.read8(0xFF07); tac != 0xF9 {
+		t.Errorf("Expected the unused TAC bits to read as 1, got %#02x", tac)
+	}
+}
+
+func TestTimerRequestsInterrupt(t *testing.T) {
+	gb := stepGameboy([]uint8{0x00})
+	gb.mem.write8(0xFF05, 0xFF)
+	gb.mem.write8(0xFF07, 0x5)
+
+	gb.updateTimer(20)
+
+	if !testBit(gb.mem.read8(0xFF0F), 2) {
+		t.Errorf("Expected the timer interrupt to be requested")
+	}
+}
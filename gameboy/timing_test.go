@@ -0,0 +1,138 @@
+package gameboy
+
+import "testing"
+
+// Published SM83 instruction timings in clock cycles, indexed by opcode. For conditional
+// instructions this is the duration when the condition holds. Illegal opcodes are 0.
+var publishedTimings = [256]int{
+	4, 12, 8, 8, 4, 4, 8, 4, 20, 8, 8, 8, 4, 4, 8, 4,
+	4, 12, 8, 8, 4, 4, 8, 4, 12, 8, 8, 8, 4, 4, 8, 4,
+	12, 12, 8, 8, 4, 4, 8, 4, 12, 8, 8, 8, 4, 4, 8, 4,
+	12, 12, 8, 8, 12, 12, 12, 4, 12, 8, 8, 8, 4, 4, 8, 4,
+	4, 4, 4, 4, 4, 4, 8, 4, 4, 4, 4, 4, 4, 4, 8, 4,
+	4, 4, 4, 4, 4, 4, 8, 4, 4, 4, 4, 4, 4, 4, 8, 4,
+	4, 4, 4, 4, 4, 4, 8, 4, 4, 4, 4, 4, 4, 4, 8, 4,
+	8, 8, 8, 8, 8, 8, 4, 8, 4, 4, 4, 4, 4, 4, 8, 4,
+	4, 4, 4, 4, 4, 4, 8, 4, 4, 4, 4, 4, 4, 4, 8, 4,
+	4, 4, 4, 4, 4, 4, 8, 4, 4, 4, 4, 4, 4, 4, 8, 4,
+	4, 4, 4, 4, 4, 4, 8, 4, 4, 4, 4, 4, 4, 4, 8, 4,
+	4, 4, 4, 4, 4, 4, 8, 4, 4, 4, 4, 4, 4, 4, 8, 4,
+	20, 12, 16, 16, 24, 16, 8, 16, 20, 16, 16, 4, 24, 24, 8, 16,
+	20, 12, 16, 0, 24, 16, 8, 16, 20, 16, 16, 0, 24, 0, 8, 16,
+	12, 12, 8, 0, 0, 16, 8, 16, 16, 4, 16, 0, 0, 0, 8, 16,
+	12, 12, 8, 4, 0, 16, 8, 16, 12, 8, 16, 4, 0, 0, 8, 16,
+}
+
+// Published durations of the conditional instructions when the condition does not hold
+var publishedNotTakenTimings = map[uint8]int{
+	0x20: 8, 0x28: 8, 0x30: 8, 0x38: 8,
+	0xc0: 8, 0xc8: 8, 0xd0: 8, 0xd8: 8,
+	0xc2: 12, 0xca: 12, 0xd2: 12, 0xda: 12,
+	0xc4: 12, 0xcc: 12, 0xd4: 12, 0xdc: 12,
+}
+
+// Sets the flags so the condition encoded in a conditional opcode (NZ, Z, NC or C) holds or not
+func setCondition(reg *register, opcode uint8, holds bool) {
+	reg.F = 0
+	switch (opcode >> 3) & 0x3 {
+	case 0:
+		reg.setZ(!holds)
+	case 1:
+		reg.setZ(holds)
+	case 2:
+		reg.setC(!holds)
+	case 3:
+		reg.setC(holds)
+	}
+}
+
+func timingGameboy(code []uint8) *Gameboy {
+	gb := &Gameboy{
+		instructions:   createInstructionTable(),
+		cbInstructions: createCBInstructionTable(),
+		mem:            dummyMemory(),
+		reg:            dummyRegs(),
+		options:        &Options{},
+	}
+	gb.reg.PC = 0xc000
+	gb.reg.SP = 0xd000
+	gb.reg.writeDuo(REG_HL, 0xc100)
+	for i, b := range code {
+		gb.mem.write8(0xc000+uint16(i), b)
+	}
+	return gb
+}
+
+func TestInstructionTimings(t *testing.T) {
+	for opcode, expected := range publishedTimings {
+		if expected == 0 || opcode == 0xcb {
+			continue
+		}
+		code := uint8(opcode)
+		// Operands point at work RAM: d8 = 0x00, a16 = 0xc200
+		gb := timingGameboy([]uint8{code, 0x00, 0xc2})
+
+		notTaken, conditional := publishedNotTakenTimings[code]
+		if conditional {
+			setCondition(gb.reg, code, true)
+		}
+		if cycles := gb.executeInstruction(); cycles != expected {
+			t.Errorf("%#02x %s took %d cycles, expected %d", code, gb.instructions[code].name, cycles, expected)
+		}
+
+		if conditional {
+			gb = timingGameboy([]uint8{code, 0x00, 0xc2})
+			setCondition(gb.reg, code, false)
+			if cycles := gb.executeInstruction(); cycles != notTaken {
+				t.Errorf("%#02x %s not taken took %d cycles, expected %d", code, gb.instructions[code].name, cycles, notTaken)
+			}
+		}
+	}
+}
+
+func TestCBInstructionTimings(t *testing.T) {
+	for opcode := 0; opcode < 256; opcode++ {
+		expected := 8
+		if opcode&0x7 == 0x6 {
+			expected = 16
+			if opcode >= 0x40 && opcode < 0x80 {
+				expected = 12
+			}
+		}
+		gb := timingGameboy([]uint8{0xcb, uint8(opcode)})
+		if cycles := gb.executeInstruction(); cycles != expected {
+			t.Errorf("CB %#02x %s took %d cycles, expected %d", opcode, gb.cbInstructions[opcode].name, cycles, expected)
+		}
+	}
+}
+
+// In M-cycle mode the memory accesses of an instruction may never take longer than the instruction
+func TestMemoryAccessCycles(t *testing.T) {
+	for opcode, expected := range publishedTimings {
+		if expected == 0 {
+			continue
+		}
+		code := uint8(opcode)
+		gb := timingGameboy([]uint8{code, 0x00, 0xc2})
+		gb.mem.clock = &clock{tick: func(cycles int) {}}
+
+		if code != 0xcb {
+			setCondition(gb.reg, code, true)
+		}
+		cycles := gb.executeInstruction()
+		if gb.mem.clock.ticked > cycles {
+			t.Errorf("%#02x %s accessed memory for %d cycles, but takes %d", code, gb.instructions[code].name, gb.mem.clock.ticked, cycles)
+		}
+	}
+
+	for opcode := range publishedTimings {
+		code := uint8(opcode)
+		gb := timingGameboy([]uint8{0xcb, code})
+		gb.mem.clock = &clock{tick: func(cycles int) {}}
+
+		cycles := gb.executeInstruction()
+		if gb.mem.clock.ticked > cycles {
+			t.Errorf("CB %#02x %s accessed memory for %d cycles, but takes %d", code, gb.cbInstructions[code].name, gb.mem.clock.ticked, cycles)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/hayeb/goboy
+
+go 1.18
+
+require (
+	github.com/alecthomas/participle v0.1.0
+	golang.org/x/exp v0.0.0-20190121172915-509febef88a4 // indirect
+)